@@ -3,15 +3,15 @@ package gdal
 /*
 #include "go_gdal.h"
 #include "gdal_version.h"
-
-#cgo linux  CFLAGS: -I/usr/include/gdal
-#cgo linux  LDFLAGS: -lgdal
-#cgo darwin pkg-config: gdal
-#cgo windows LDFLAGS: -lgdal.dll
 */
 import "C"
 import (
+	"context"
 	"fmt"
+	"runtime"
+	"runtime/cgo"
+	"strconv"
+	"strings"
 	"unsafe"
 )
 
@@ -28,11 +28,15 @@ func ComputeMedianCutPCT(
 	ct ColorTable,
 	progress ProgressFunc,
 	data interface{},
-) int {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
-	}
+) error {
+	defer runtime.KeepAlive(red)
+	defer runtime.KeepAlive(green)
+	defer runtime.KeepAlive(blue)
+	defer runtime.KeepAlive(ct)
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
 
+	defer captureErrors()()
 	err := C.GDALComputeMedianCutPCT(
 		red.cval,
 		green.cval,
@@ -40,10 +44,10 @@ func ComputeMedianCutPCT(
 		nil,
 		C.int(colors),
 		ct.cval,
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		pf,
+		pa,
 	)
-	return int(err)
+	return cplError(C.CPLErr(err))
 }
 
 // 24bit to 8bit conversion with dithering
@@ -52,25 +56,31 @@ func DitherRGB2PCT(
 	ct ColorTable,
 	progress ProgressFunc,
 	data interface{},
-) int {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
-	}
+) error {
+	defer runtime.KeepAlive(red)
+	defer runtime.KeepAlive(green)
+	defer runtime.KeepAlive(blue)
+	defer runtime.KeepAlive(target)
+	defer runtime.KeepAlive(ct)
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
 
+	defer captureErrors()()
 	err := C.GDALDitherRGB2PCT(
 		red.cval,
 		green.cval,
 		blue.cval,
 		target.cval,
 		ct.cval,
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		pf,
+		pa,
 	)
-	return int(err)
+	return cplError(C.CPLErr(err))
 }
 
 // Compute checksum for image region
 func (rb RasterBand) Checksum(xOff, yOff, xSize, ySize int) int {
+	defer runtime.KeepAlive(rb)
 	sum := C.GDALChecksumImage(rb.cval, C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize))
 	return int(sum)
 }
@@ -82,9 +92,10 @@ func (src RasterBand) ComputeProximity(
 	progress ProgressFunc,
 	data interface{},
 ) error {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
-	}
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(dest)
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
 
 	length := len(options)
 	opts := make([]*C.char, length+1)
@@ -94,14 +105,29 @@ func (src RasterBand) ComputeProximity(
 	}
 	opts[length] = (*C.char)(unsafe.Pointer(nil))
 
+	defer captureErrors()()
 	err := C.GDALComputeProximity(
 		src.cval,
 		dest.cval,
 		(**C.char)(unsafe.Pointer(&opts[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		pf,
+		pa,
 	)
-	return err
+	return cplError(err)
+}
+
+// Compute the proximity of all pixels in the image to a set of pixels in
+// the source image, stopping when ctx is done
+func (src RasterBand) ComputeProximityContext(
+	ctx context.Context,
+	dest RasterBand,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	return runContext(ctx, progress, data, func(progress ProgressFunc) error {
+		return src.ComputeProximity(dest, options, progress, nil)
+	})
 }
 
 // Fill selected raster regions by interpolation from the edges
@@ -113,9 +139,10 @@ func (src RasterBand) FillNoData(
 	progress ProgressFunc,
 	data interface{},
 ) error {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
-	}
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(mask)
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
 
 	length := len(options)
 	opts := make([]*C.char, length+1)
@@ -125,6 +152,7 @@ func (src RasterBand) FillNoData(
 	}
 	opts[length] = (*C.char)(unsafe.Pointer(nil))
 
+	defer captureErrors()()
 	err := C.GDALFillNodata(
 		src.cval,
 		mask.cval,
@@ -132,10 +160,10 @@ func (src RasterBand) FillNoData(
 		0,
 		C.int(iterations),
 		(**C.char)(unsafe.Pointer(&opts[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		pf,
+		pa,
 	)
-	return error(err)
+	return cplError(err)
 }
 
 // Create polygon coverage from raster data using an integer buffer
@@ -147,9 +175,11 @@ func (src RasterBand) Polygonize(
 	progress ProgressFunc,
 	data interface{},
 ) error {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
-	}
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(mask)
+	defer runtime.KeepAlive(layer)
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
 
 	length := len(options)
 	opts := make([]*C.char, length+1)
@@ -159,16 +189,33 @@ func (src RasterBand) Polygonize(
 	}
 	opts[length] = (*C.char)(unsafe.Pointer(nil))
 
+	defer captureErrors()()
 	err := C.GDALPolygonize(
 		src.cval,
 		mask.cval,
 		layer.cval,
 		C.int(fieldIndex),
 		(**C.char)(unsafe.Pointer(&opts[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		pf,
+		pa,
 	)
-	return error(err)
+	return cplError(err)
+}
+
+// Create polygon coverage from raster data using an integer buffer,
+// stopping when ctx is done
+func (src RasterBand) PolygonizeContext(
+	ctx context.Context,
+	mask RasterBand,
+	layer Layer,
+	fieldIndex int,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	return runContext(ctx, progress, data, func(progress ProgressFunc) error {
+		return src.Polygonize(mask, layer, fieldIndex, options, progress, nil)
+	})
 }
 
 // Create polygon coverage from raster data using a floating point buffer
@@ -180,9 +227,11 @@ func (src RasterBand) FPolygonize(
 	progress ProgressFunc,
 	data interface{},
 ) error {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
-	}
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(mask)
+	defer runtime.KeepAlive(layer)
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
 
 	length := len(options)
 	opts := make([]*C.char, length+1)
@@ -192,16 +241,17 @@ func (src RasterBand) FPolygonize(
 	}
 	opts[length] = (*C.char)(unsafe.Pointer(nil))
 
+	defer captureErrors()()
 	err := C.GDALFPolygonize(
 		src.cval,
 		mask.cval,
 		layer.cval,
 		C.int(fieldIndex),
 		(**C.char)(unsafe.Pointer(&opts[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		pf,
+		pa,
 	)
-	return error(err)
+	return cplError(err)
 }
 
 // Removes small raster polygons
@@ -212,9 +262,11 @@ func (src RasterBand) SieveFilter(
 	progress ProgressFunc,
 	data interface{},
 ) error {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
-	}
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(mask)
+	defer runtime.KeepAlive(dest)
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
 
 	length := len(options)
 	opts := make([]*C.char, length+1)
@@ -224,6 +276,7 @@ func (src RasterBand) SieveFilter(
 	}
 	opts[length] = (*C.char)(unsafe.Pointer(nil))
 
+	defer captureErrors()()
 	err := C.GDALSieveFilter(
 		src.cval,
 		mask.cval,
@@ -231,19 +284,83 @@ func (src RasterBand) SieveFilter(
 		C.int(threshold),
 		C.int(connectedness),
 		(**C.char)(unsafe.Pointer(&opts[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		pf,
+		pa,
 	)
-	return error(err)
+	return cplError(err)
+}
+
+// Removes small raster polygons, stopping when ctx is done
+func (src RasterBand) SieveFilterContext(
+	ctx context.Context,
+	mask, dest RasterBand,
+	threshold, connectedness int,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	return runContext(ctx, progress, data, func(progress ProgressFunc) error {
+		return src.SieveFilter(mask, dest, threshold, connectedness, options, progress, nil)
+	})
 }
 
 /* --------------------------------------------- */
 /* Warp functions                                */
 /* --------------------------------------------- */
 
-type WarpOptions *C.GDALWarpOptions 
+// Options of Warp and ReprojectImage.  The zero value warps all bands with
+// nearest neighbour resampling and an exact transformation.
+type WarpOptions struct {
+	// Resampling algorithm
+	ResampleAlg ResampleAlg
+	// Data type of the warp buffers, Unknown for the largest band type
+	WorkingDataType DataType
+	// Memory used for warp buffers in bytes, 0 for the GDAL default
+	MemoryLimit float64
+
+	// Source bands warped into DstBands, numbered from 1.  Empty SrcBands
+	// stand for all bands of the source, empty DstBands for SrcBands.
+	SrcBands []int
+	DstBands []int
+	// Nodata value of each source band, or one value for all bands.  Source
+	// pixels set to nodata are not warped.
+	SrcNoData []float64
+	// Nodata value of each destination band, or one value for all bands
+	DstNoData []float64
+	// Alpha bands, 0 for none
+	SrcAlphaBand int
+	DstAlphaBand int
+
+	// Polygon in the georeferenced coordinates of the destination, outside
+	// of which no pixel is warped, or a null geometry
+	Cutline Geometry
+	// Distance in pixels over which the cutline blends in
+	CutlineBlendDistance float64
+
+	// Transformer from source pixels to destination pixels, used for every
+	// source.  If nil, Warp creates one per source with
+	// CreateGenImgProjTransformer2 and TransformerOptions.
+	Transformer Transformer
+	// Options of the transformers created by Warp, such as SRC_SRS
+	TransformerOptions []string
+	// Maximum error in pixels of the approximation of the transformers
+	// created by Warp, 0 for exact transformations (gdalwarp uses 0.125)
+	MaxError float64
 
-// Reproject image
+	// Number of warping threads, 0 for one, negative for all CPUs
+	NumThreads int
+	// Value the destination is filled with before warping, "NO_DATA" for
+	// DstNoData, or "" to warp over the current content
+	InitDest string
+	// Other warp options, such as SOURCE_EXTRA=1 or CUTLINE_ALL_TOUCHED=TRUE
+	Options []string
+}
+
+// Reproject image.  Cutline, Transformer, TransformerOptions and MaxError of
+// options are not supported, see Warp instead.  The ResampleAlg and
+// MemoryLimit of options must be left to zero or match resampleAlg and
+// memLimit.  Without SrcBands and DstBands, GDAL warps all bands and
+// detects alpha bands itself.
 func (src Dataset) ReprojectImage(
 	srcProjWKT string,
 	dst Dataset,
@@ -252,15 +369,14 @@ func (src Dataset) ReprojectImage(
 	memLimit, maxError float64,
 	progress ProgressFunc,
 	data interface{},
-	options WarpOptions,
+	options *WarpOptions,
 ) error {
-	var pf C.GDALProgressFunc
-	var pa unsafe.Pointer
-	if progress != nil {
-		pf = C.goGDALProgressFuncProxyB()
-		pa = unsafe.Pointer(&goGDALProgressFuncProxyArgs{progress, data})
-	}
-	
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(dst)
+	defer runtime.KeepAlive(options)
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
+
 	var c_srcWKT, c_dstWKT *C.char
 	if srcProjWKT != "" {
 		c_srcWKT = C.CString(srcProjWKT)
@@ -271,6 +387,26 @@ func (src Dataset) ReprojectImage(
 		defer C.free(unsafe.Pointer(c_dstWKT))
 	}
 
+	var psWO *C.GDALWarpOptions
+	if options != nil {
+		if options.Cutline.cval != nil || options.Transformer != nil ||
+			options.TransformerOptions != nil || options.MaxError != 0 {
+			return newError(CPLE_NotSupported, "Error: ReprojectImage does not support cutlines and transformers, use Warp")
+		}
+		if options.ResampleAlg != 0 && options.ResampleAlg != resampleAlg {
+			return newError(CPLE_IllegalArg, "Error: resampling algorithm %d of options conflicts with %d", options.ResampleAlg, resampleAlg)
+		}
+		if options.MemoryLimit != 0 && options.MemoryLimit != memLimit {
+			return newError(CPLE_IllegalArg, "Error: memory limit %v of options conflicts with %v", options.MemoryLimit, memLimit)
+		}
+		var err error
+		if psWO, err = options.cWarpOptions(src, dst, true, true); err != nil {
+			return err
+		}
+		defer C.GDALDestroyWarpOptions(psWO)
+	}
+
+	defer captureErrors()()
 	err := C.GDALReprojectImage(
 		src.cval,
 		c_srcWKT,
@@ -279,81 +415,1587 @@ func (src Dataset) ReprojectImage(
 		C.GDALResampleAlg(resampleAlg),
 		C.double(memLimit),
 		C.double(maxError),
-		pf, 
+		pf,
 		pa,
-		options,
+		psWO,
 	)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
+	}
+	return nil
+}
+
+// Reproject image, stopping when ctx is done
+func (src Dataset) ReprojectImageContext(
+	ctx context.Context,
+	srcProjWKT string,
+	dst Dataset,
+	dstProjWKT string,
+	resampleAlg ResampleAlg,
+	memLimit, maxError float64,
+	progress ProgressFunc,
+	data interface{},
+	options *WarpOptions,
+) error {
+	return runContext(ctx, progress, data, func(progress ProgressFunc) error {
+		return src.ReprojectImage(srcProjWKT, dst, dstProjWKT, resampleAlg, memLimit, maxError, progress, nil, options)
+	})
+}
+
+// Warp each of srcs in turn into the whole of dst, so that the later
+// sources are painted over the earlier ones, as gdalwarp does.  InitDest
+// applies before the first source only.  options may be nil.
+func Warp(
+	srcs []Dataset,
+	dst Dataset,
+	options *WarpOptions,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	if options == nil {
+		options = &WarpOptions{}
+	}
+	if len(srcs) == 0 {
+		return newError(CPLE_IllegalArg, "Error: no source dataset to warp")
+	}
+
+	weights := make([]float64, len(srcs))
+	for i, src := range srcs {
+		weights[i] = float64(src.RasterXSize()) * float64(src.RasterYSize())
+	}
+	var steps []ProgressFunc
+	if progress != nil {
+		steps = SplitProgress(progress, data, weights...)
+	}
+
+	for i, src := range srcs {
+		var step ProgressFunc
+		if steps != nil {
+			step = steps[i]
+		}
+		if err := options.warp(src, dst, i == 0, step); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-//Unimplemented: CreateGenImgProjTransformer
-//Unimplemented: CreateGenImgProjTransformer2
-//Unimplemented: CreateGenImgProjTransformer3
-//Unimplemented: SetGenImgProjTransformerDstGeoTransform
-//Unimplemented: DestroyGenImgProjTransformer
-//Unimplemented: GenImgProjTransform
+// Warp srcs into dst, stopping when ctx is done
+func WarpContext(
+	ctx context.Context,
+	srcs []Dataset,
+	dst Dataset,
+	options *WarpOptions,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	return runContext(ctx, progress, data, func(progress ProgressFunc) error {
+		return Warp(srcs, dst, options, progress, nil)
+	})
+}
+
+// Warp a single source into the whole of dst
+func (options *WarpOptions) warp(src, dst Dataset, first bool, progress ProgressFunc) error {
+	defer runtime.KeepAlive(options)
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(dst)
+	transformer := options.Transformer
+	if transformer == nil {
+		genImgProj, err := CreateGenImgProjTransformer2(src, dst, options.TransformerOptions)
+		if err != nil {
+			return err
+		}
+		transformer = genImgProj
+		if options.MaxError > 0 {
+			approx, err := CreateApproxTransformer(genImgProj, options.MaxError)
+			if err != nil {
+				genImgProj.Destroy()
+				return err
+			}
+			transformer = approx
+		}
+		defer transformer.Destroy()
+	}
 
-//Unimplemented: CreateReprojectionTransformer
-//Unimplemented: DestroyReprojection
-//Unimplemented: ReprojectionTransform
-//Unimplemented: CreateGCPTransformer
-//Unimplemented: CreateGCPRefineTransformer
-//Unimplemented: DestroyGCPTransformer
-//Unimplemented: GCPTransform
+	psWO, err := options.cWarpOptions(src, dst, first, false)
+	if err != nil {
+		return err
+	}
+	defer C.GDALDestroyWarpOptions(psWO)
+	psWO.pfnTransformer = C.goGDALUseTransformer()
+	psWO.pTransformerArg = transformer.handle().cval
 
-//Unimplemented: CreateTPSTransformer
-//Unimplemented: DestroyTPSTransformer
-//Unimplemented: TPSTransform
+	if options.Cutline.cval != nil {
+		cutline, err := warpCutline(options.Cutline, dst, transformer)
+		if err != nil {
+			return err
+		}
+		// destroyed along with the warp options
+		cutline.owner.disown()
+		psWO.hCutline = unsafe.Pointer(cutline.cval)
+		psWO.dfCutlineBlendDist = C.double(options.CutlineBlendDistance)
+	}
 
-//Unimplemented: CreateRPCTransformer
-//Unimplemented: DestroyRPCTransformer
-//Unimplemented: RPCTransform
+	pf, pa, release := progressProxy(progress, nil)
+	defer release()
+	// keep the dummy progress of GDALCreateWarpOptions, warp options
+	// without progress function are invalid
+	if pf != nil {
+		psWO.pfnProgress = pf
+		psWO.pProgressArg = pa
+	}
 
-//Unimplemented: CreateGeoLocTransformer
-//Unimplemented: DestroyGeoLocTransformer
-//Unimplemented: GeoLocTransform
+	defer captureErrors()()
+	operation := C.GDALCreateWarpOperation(psWO)
+	if operation == nil {
+		return lastError(CPLE_AppDefined, "Error: invalid warp options")
+	}
+	defer C.GDALDestroyWarpOperation(operation)
 
-//Unimplemented: CreateApproxTransformer
-//Unimplemented: DestroyApproxTransformer
-//Unimplemented: ApproxTransform
+	cErr := C.GDALChunkAndWarpImage(operation, 0, 0, C.int(dst.RasterXSize()), C.int(dst.RasterYSize()))
+	return cplError(cErr)
+}
 
-//Unimplemented: SimpleImageWarp
-//Unimplemented: SuggestedWarpOutput
-//Unimplemented: SuggsetedWarpOutput2
-//Unimplemented: SerializeTransformer
-//Unimplemented: DeserializeTransformer
+// Fill warp options from src to dst, except the transformer, cutline and
+// progress, to be destroyed with GDALDestroyWarpOptions.  With
+// defaultBands, bands that are not given are left for GDAL to choose.
+func (options *WarpOptions) cWarpOptions(src, dst Dataset, initDest, defaultBands bool) (*C.GDALWarpOptions, error) {
+	defer runtime.KeepAlive(options)
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(dst)
+	srcBands := options.SrcBands
+	if len(srcBands) == 0 {
+		srcBands = make([]int, src.RasterCount())
+		for i := range srcBands {
+			srcBands[i] = i + 1
+		}
+	}
+	dstBands := options.DstBands
+	if len(dstBands) == 0 {
+		dstBands = srcBands
+	}
+	if len(dstBands) != len(srcBands) {
+		return nil, newError(CPLE_IllegalArg, "Error: got %d source and %d destination bands", len(srcBands), len(dstBands))
+	}
+	srcNoData, err := bandValues(options.SrcNoData, len(srcBands), "source nodata")
+	if err != nil {
+		return nil, err
+	}
+	dstNoData, err := bandValues(options.DstNoData, len(srcBands), "destination nodata")
+	if err != nil {
+		return nil, err
+	}
+
+	warpOptions := append([]string{}, options.Options...)
+	if options.NumThreads < 0 {
+		warpOptions = append(warpOptions, "NUM_THREADS=ALL_CPUS")
+	} else if options.NumThreads > 0 {
+		warpOptions = append(warpOptions, fmt.Sprintf("NUM_THREADS=%d", options.NumThreads))
+	}
+	if initDest && options.InitDest != "" {
+		warpOptions = append(warpOptions, "INIT_DEST="+options.InitDest)
+	}
+
+	psWO := C.GDALCreateWarpOptions()
+	psWO.papszWarpOptions = cStringList(warpOptions)
+	psWO.dfWarpMemoryLimit = C.double(options.MemoryLimit)
+	psWO.eResampleAlg = C.GDALResampleAlg(options.ResampleAlg)
+	psWO.eWorkingDataType = C.GDALDataType(options.WorkingDataType)
+	psWO.hSrcDS = src.cval
+	psWO.hDstDS = dst.cval
+	if !defaultBands || len(options.SrcBands) != 0 || len(options.DstBands) != 0 {
+		psWO.nBandCount = C.int(len(srcBands))
+		psWO.panSrcBands = cIntArray(srcBands)
+		psWO.panDstBands = cIntArray(dstBands)
+	}
+	psWO.nSrcAlphaBand = C.int(options.SrcAlphaBand)
+	psWO.nDstAlphaBand = C.int(options.DstAlphaBand)
+	if srcNoData != nil {
+		psWO.padfSrcNoDataReal = cDoubleArray(srcNoData)
+		psWO.padfSrcNoDataImag = cDoubleArray(make([]float64, len(srcNoData)))
+	}
+	if dstNoData != nil {
+		psWO.padfDstNoDataReal = cDoubleArray(dstNoData)
+		psWO.padfDstNoDataImag = cDoubleArray(make([]float64, len(dstNoData)))
+	}
+	return psWO, nil
+}
+
+// Expand a single value to all bands
+func bandValues(values []float64, count int, name string) ([]float64, error) {
+	switch len(values) {
+	case 0:
+		return nil, nil
+	case 1:
+		expanded := make([]float64, count)
+		for i := range expanded {
+			expanded[i] = values[0]
+		}
+		return expanded, nil
+	case count:
+		return values, nil
+	}
+	return nil, newError(CPLE_IllegalArg, "Error: got %d %s values for %d bands", len(values), name, count)
+}
+
+// Convert a cutline from the georeferenced coordinates of dst to the pixels
+// of the source of transformer
+func warpCutline(cutline Geometry, dst Dataset, transformer Transformer) (Geometry, error) {
+	geoTransform, ok := dst.GeoTransform()
+	if !ok {
+		return Geometry{}, newError(CPLE_AppDefined, "Error: a cutline requires a georeferenced destination")
+	}
+	inverse, err := geoTransform.Invert()
+	if err != nil {
+		return Geometry{}, err
+	}
+
+	pixels := cutline.Clone()
+	err = transformGeometryPoints(pixels, func(x, y, z []float64) error {
+		for i := range x {
+			x[i], y[i] = inverse.Apply(x[i], y[i])
+		}
+		ok, err := transformer.Transform(true, x, y, z)
+		if err != nil {
+			return err
+		}
+		for _, transformed := range ok {
+			if !transformed {
+				return newError(CPLE_AppDefined, "Error: cutline falls outside of the source")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		pixels.Destroy()
+		return Geometry{}, err
+	}
+	return pixels, nil
+}
+
+// Transform the vertices of a geometry and of its parts in place
+func transformGeometryPoints(geom Geometry, transform func(x, y, z []float64) error) error {
+	for i := 0; i < geom.GeometryCount(); i++ {
+		if err := transformGeometryPoints(geom.Geometry(i), transform); err != nil {
+			return err
+		}
+	}
+
+	count := geom.PointCount()
+	if count == 0 {
+		return nil
+	}
+	x := make([]float64, count)
+	y := make([]float64, count)
+	z := make([]float64, count)
+	for i := range x {
+		x[i], y[i], z[i] = geom.Point(i)
+	}
+	if err := transform(x, y, z); err != nil {
+		return err
+	}
+	is3D := geom.CoordinateDimension() == 3
+	for i := range x {
+		if is3D {
+			geom.SetPoint(i, x[i], y[i], z[i])
+		} else {
+			geom.SetPoint2D(i, x[i], y[i])
+		}
+	}
+	return nil
+}
+
+// Copy values to an array allocated by VSIMalloc, to be freed by GDAL
+func cIntArray(values []int) *C.int {
+	array := (*C.int)(C.VSIMalloc(C.size_t(len(values)+1) * C.sizeof_int))
+	list := unsafe.Slice(array, len(values))
+	for i, value := range values {
+		list[i] = C.int(value)
+	}
+	return array
+}
+
+// Copy values to an array allocated by VSIMalloc, to be freed by GDAL
+func cDoubleArray(values []float64) *C.double {
+	array := (*C.double)(C.VSIMalloc(C.size_t(len(values)+1) * C.sizeof_double))
+	copy(unsafe.Slice((*float64)(unsafe.Pointer(array)), len(values)), values)
+	return array
+}
+
+/* --------------------------------------------- */
+/* Transformer functions                         */
+/* --------------------------------------------- */
+
+// Transformer between coordinate systems, as used by the warper to map
+// destination pixels to source pixels.  It is implemented by the
+// transformers of GDAL created by the functions below.
+type Transformer interface {
+	// Transform points in place, from source to destination coordinates,
+	// or back if dstToSrc.  z may be nil.  The returned slice tells which
+	// points were transformed; an error is returned only if none was.
+	Transform(dstToSrc bool, x, y, z []float64) ([]bool, error)
+	// Destroy the transformer.  Destroying it twice does nothing.
+	Destroy()
+	// Fetch the transformer created by GDAL
+	handle() transformer
+}
+
+// Transformer created by GDAL, embedded by the concrete transformers
+type transformer struct {
+	cval  unsafe.Pointer
+	owner *owner
+}
+
+// Wrap a transformer owned by the caller, or return an error if GDAL failed
+// to create it
+func ownTransformer(cval unsafe.Pointer, kind string) (transformer, error) {
+	if cval == nil {
+		return transformer{}, lastError(CPLE_AppDefined, "Error: cannot create %s transformer", kind)
+	}
+	return transformer{cval, own(func() { C.GDALDestroyTransformer(cval) })}, nil
+}
+
+func (t transformer) Transform(dstToSrc bool, x, y, z []float64) ([]bool, error) {
+	defer runtime.KeepAlive(t)
+	if t.cval == nil {
+		return nil, newError(CPLE_ObjectNull, "Error: transformer is not valid")
+	}
+	if len(y) != len(x) || (z != nil && len(z) != len(x)) {
+		return nil, newError(CPLE_IllegalArg, "Error: got %d x, %d y and %d z coordinates", len(x), len(y), len(z))
+	}
+	if len(x) == 0 {
+		return []bool{}, nil
+	}
+	// transformers may write z even for 2D points
+	if z == nil {
+		z = make([]float64, len(x))
+	}
+	success := make([]C.int, len(x))
+
+	defer captureErrors()()
+	ret := C.GDALUseTransformer(
+		t.cval,
+		BoolToCInt(dstToSrc),
+		C.int(len(x)),
+		(*C.double)(unsafe.Pointer(&x[0])),
+		(*C.double)(unsafe.Pointer(&y[0])),
+		(*C.double)(unsafe.Pointer(&z[0])),
+		&success[0],
+	)
+
+	ok := make([]bool, len(x))
+	transformed := false
+	for i, s := range success {
+		ok[i] = s != 0
+		transformed = transformed || ok[i]
+	}
+	if ret == 0 && !transformed {
+		return ok, lastError(CPLE_AppDefined, "Error: failed to transform %d points", len(x))
+	}
+	return ok, nil
+}
+
+func (t transformer) Destroy() {
+	t.owner.release()
+}
+
+func (t transformer) handle() transformer {
+	return t
+}
+
+// Transformer from the pixels of a source raster to the pixels of a
+// destination raster, going through their georeferencing
+type GenImgProjTransformer struct {
+	transformer
+}
+
+// Create a transformer from the pixels of src to the pixels of dst, or to
+// georeferenced coordinates if dst is a null dataset.  Empty WKT strings
+// stand for the projections of the datasets.  GCPs are used if allowed and
+// src has no geotransform, with a polynomial of the given order, or 0 for
+// automatic.
+func CreateGenImgProjTransformer(
+	src Dataset,
+	srcWKT string,
+	dst Dataset,
+	dstWKT string,
+	gcpUseOK bool,
+	gcpErrorThreshold float64,
+	order int,
+) (GenImgProjTransformer, error) {
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(dst)
+	var cSrcWKT, cDstWKT *C.char
+	if srcWKT != "" {
+		cSrcWKT = C.CString(srcWKT)
+		defer C.free(unsafe.Pointer(cSrcWKT))
+	}
+	if dstWKT != "" {
+		cDstWKT = C.CString(dstWKT)
+		defer C.free(unsafe.Pointer(cDstWKT))
+	}
+
+	defer captureErrors()()
+	t, err := ownTransformer(C.GDALCreateGenImgProjTransformer(
+		src.cval,
+		cSrcWKT,
+		dst.cval,
+		cDstWKT,
+		BoolToCInt(gcpUseOK),
+		C.double(gcpErrorThreshold),
+		C.int(order),
+	), "GenImgProj")
+	return GenImgProjTransformer{t}, err
+}
+
+// Create a transformer from the pixels of src to the pixels of dst, or to
+// georeferenced coordinates if dst is a null dataset, configured by
+// options such as SRC_SRS, DST_SRS, METHOD=GCP_TPS or RPC_HEIGHT
+func CreateGenImgProjTransformer2(src, dst Dataset, options []string) (GenImgProjTransformer, error) {
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(dst)
+	opts := cStringList(options)
+	defer C.CSLDestroy(opts)
+
+	defer captureErrors()()
+	t, err := ownTransformer(C.GDALCreateGenImgProjTransformer2(src.cval, dst.cval, opts), "GenImgProj")
+	return GenImgProjTransformer{t}, err
+}
+
+// Create a transformer between the pixels of two rasters described by
+// their projections and geotransforms
+func CreateGenImgProjTransformer3(
+	srcWKT string,
+	srcGeoTransform GeoTransform,
+	dstWKT string,
+	dstGeoTransform GeoTransform,
+) (GenImgProjTransformer, error) {
+	cSrcWKT := C.CString(srcWKT)
+	defer C.free(unsafe.Pointer(cSrcWKT))
+	cDstWKT := C.CString(dstWKT)
+	defer C.free(unsafe.Pointer(cDstWKT))
+
+	defer captureErrors()()
+	t, err := ownTransformer(C.GDALCreateGenImgProjTransformer3(
+		cSrcWKT,
+		(*C.double)(unsafe.Pointer(&srcGeoTransform[0])),
+		cDstWKT,
+		(*C.double)(unsafe.Pointer(&dstGeoTransform[0])),
+	), "GenImgProj")
+	return GenImgProjTransformer{t}, err
+}
+
+// Change the geotransform of the destination raster
+func (t GenImgProjTransformer) SetDstGeoTransform(geoTransform GeoTransform) {
+	defer runtime.KeepAlive(t)
+	C.GDALSetGenImgProjTransformerDstGeoTransform(
+		t.cval,
+		(*C.double)(unsafe.Pointer(&geoTransform[0])),
+	)
+}
+
+// Transformer between two projections
+type ReprojectionTransformer struct {
+	transformer
+}
+
+// Create a transformer between georeferenced coordinates in two
+// projections, given as WKT
+func CreateReprojectionTransformer(srcWKT, dstWKT string) (ReprojectionTransformer, error) {
+	cSrcWKT := C.CString(srcWKT)
+	defer C.free(unsafe.Pointer(cSrcWKT))
+	cDstWKT := C.CString(dstWKT)
+	defer C.free(unsafe.Pointer(cDstWKT))
+
+	defer captureErrors()()
+	t, err := ownTransformer(C.GDALCreateReprojectionTransformer(cSrcWKT, cDstWKT), "reprojection")
+	return ReprojectionTransformer{t}, err
+}
+
+// Transformer fitting a polynomial to ground control points
+type GCPTransformer struct {
+	transformer
+}
+
+// Create a transformer from pixels to georeferenced coordinates, or back if
+// reversed, fitting a polynomial of order 1 to 3 to the GCPs, or of the
+// highest order allowed by their number if order is 0
+func CreateGCPTransformer(gcps []GCP, order int, reversed bool) (GCPTransformer, error) {
+	cGCPs, free := cGCPList(gcps)
+	defer free()
+
+	defer captureErrors()()
+	t, err := ownTransformer(C.GDALCreateGCPTransformer(
+		C.int(len(gcps)),
+		cGCPs,
+		C.int(order),
+		BoolToCInt(reversed),
+	), "GCP")
+	return GCPTransformer{t}, err
+}
+
+// Create a GCP transformer that drops the worst GCPs until the remaining
+// ones fit the polynomial within tolerance, keeping at least minimumGCPs
+func CreateGCPRefineTransformer(
+	gcps []GCP,
+	order int,
+	reversed bool,
+	tolerance float64,
+	minimumGCPs int,
+) (GCPTransformer, error) {
+	cGCPs, free := cGCPList(gcps)
+	defer free()
+
+	defer captureErrors()()
+	t, err := ownTransformer(C.GDALCreateGCPRefineTransformer(
+		C.int(len(gcps)),
+		cGCPs,
+		C.int(order),
+		BoolToCInt(reversed),
+		C.double(tolerance),
+		C.int(minimumGCPs),
+	), "GCP")
+	return GCPTransformer{t}, err
+}
+
+// Transformer interpolating ground control points with thin plate splines
+type TPSTransformer struct {
+	transformer
+}
+
+// Create a transformer from pixels to georeferenced coordinates, or back if
+// reversed, going exactly through every GCP
+func CreateTPSTransformer(gcps []GCP, reversed bool) (TPSTransformer, error) {
+	cGCPs, free := cGCPList(gcps)
+	defer free()
+
+	defer captureErrors()()
+	t, err := ownTransformer(C.GDALCreateTPSTransformer(
+		C.int(len(gcps)),
+		cGCPs,
+		BoolToCInt(reversed),
+	), "TPS")
+	return TPSTransformer{t}, err
+}
+
+// Transformer using rational polynomial coefficients
+type RPCTransformer struct {
+	transformer
+}
+
+// Create a transformer from pixels to WGS84 longitudes and latitudes, or
+// back if reversed, from the RPC metadata domain of a dataset.  Options
+// such as RPC_HEIGHT or RPC_DEM set the elevation of the points.
+func CreateRPCTransformer(
+	rpc map[string]string,
+	reversed bool,
+	pixErrThreshold float64,
+	options []string,
+) (RPCTransformer, error) {
+	rpcList := cMetadataList(rpc)
+	defer C.CSLDestroy(rpcList)
+	opts := cStringList(options)
+	defer C.CSLDestroy(opts)
+
+	defer captureErrors()()
+	t, err := ownTransformer(C.goGDALCreateRPCTransformer(
+		rpcList,
+		BoolToCInt(reversed),
+		C.double(pixErrThreshold),
+		opts,
+	), "RPC")
+	return RPCTransformer{t}, err
+}
+
+// Transformer using geolocation arrays
+type GeoLocTransformer struct {
+	transformer
+}
+
+// Create a transformer from the pixels of base to georeferenced
+// coordinates, or back if reversed, from the GEOLOCATION metadata domain
+// of base, which names the datasets holding the coordinates of each pixel
+func CreateGeoLocTransformer(base Dataset, geolocation map[string]string, reversed bool) (GeoLocTransformer, error) {
+	defer runtime.KeepAlive(base)
+	geolocList := cMetadataList(geolocation)
+	defer C.CSLDestroy(geolocList)
+
+	defer captureErrors()()
+	t, err := ownTransformer(C.GDALCreateGeoLocTransformer(
+		base.cval,
+		geolocList,
+		BoolToCInt(reversed),
+	), "GeoLoc")
+	return GeoLocTransformer{t}, err
+}
+
+// Transformer approximating another one by linear interpolation
+type ApproxTransformer struct {
+	transformer
+}
+
+// Create a transformer approximating base along lines of points, within
+// maxError pixels, which is much faster for warping.  The approximating
+// transformer takes base over: base is destroyed along with it, and must
+// not be used or destroyed on its own afterwards.
+func CreateApproxTransformer(base Transformer, maxError float64) (ApproxTransformer, error) {
+	defer runtime.KeepAlive(base)
+	if base == nil || base.handle().cval == nil || !base.handle().owner.owned() {
+		return ApproxTransformer{}, newError(CPLE_ObjectNull, "Error: transformer is not valid or not owned")
+	}
+
+	defer captureErrors()()
+	cval := C.GDALCreateApproxTransformer(C.goGDALUseTransformer(), base.handle().cval, C.double(maxError))
+	if cval == nil {
+		return ApproxTransformer{}, lastError(CPLE_AppDefined, "Error: cannot create approximate transformer")
+	}
+	base.handle().owner.disown()
+	C.GDALApproxTransformerOwnsSubtransformer(cval, C.TRUE)
+	return ApproxTransformer{transformer{cval, own(func() { C.GDALDestroyTransformer(cval) })}}, nil
+}
+
+// Suggest the size and geotransform of a raster covering the whole of src,
+// transformed from its pixels to georeferenced coordinates by transformer,
+// such as a GenImgProjTransformer with a null destination dataset
+func SuggestedWarpOutput(src Dataset, transformer Transformer) (xSize, ySize int, geoTransform GeoTransform, err error) {
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(transformer)
+	if transformer == nil || transformer.handle().cval == nil {
+		return 0, 0, geoTransform, newError(CPLE_ObjectNull, "Error: transformer is not valid")
+	}
+
+	var cXSize, cYSize C.int
+	defer captureErrors()()
+	cErr := C.GDALSuggestedWarpOutput(
+		src.cval,
+		C.goGDALUseTransformer(),
+		transformer.handle().cval,
+		(*C.double)(unsafe.Pointer(&geoTransform[0])),
+		&cXSize,
+		&cYSize,
+	)
+	if cErr != 0 {
+		return 0, 0, GeoTransform{}, cplError(cErr)
+	}
+	return int(cXSize), int(cYSize), geoTransform, nil
+}
+
+// Serialize a transformer to XML, so that it can be stored and recreated by
+// DeserializeTransformer
+func SerializeTransformer(transformer Transformer) (string, error) {
+	defer runtime.KeepAlive(transformer)
+	if transformer == nil || transformer.handle().cval == nil {
+		return "", newError(CPLE_ObjectNull, "Error: transformer is not valid")
+	}
 
+	defer captureErrors()()
+	tree := C.GDALSerializeTransformer(C.goGDALUseTransformer(), transformer.handle().cval)
+	if tree == nil {
+		return "", lastError(CPLE_AppDefined, "Error: cannot serialize transformer")
+	}
+	defer C.CPLDestroyXMLNode(tree)
+
+	cXML := C.CPLSerializeXMLTree(tree)
+	defer C.VSIFree(unsafe.Pointer(cXML))
+	return C.GoString(cXML), nil
+}
+
+// Create a transformer from the XML written by SerializeTransformer.  The
+// result has the concrete type matching the XML, such as
+// GenImgProjTransformer.
+func DeserializeTransformer(xml string) (Transformer, error) {
+	cXML := C.CString(xml)
+	defer C.free(unsafe.Pointer(cXML))
+
+	defer captureErrors()()
+	tree := C.CPLParseXMLString(cXML)
+	if tree == nil {
+		return nil, lastError(CPLE_AppDefined, "Error: cannot parse transformer XML")
+	}
+	defer C.CPLDestroyXMLNode(tree)
+
+	var pfn C.GDALTransformerFunc
+	var cval unsafe.Pointer
+	if cErr := C.GDALDeserializeTransformer(tree, &pfn, &cval); cErr != 0 || cval == nil {
+		return nil, lastError(CPLE_AppDefined, "Error: cannot deserialize transformer")
+	}
+
+	t := transformer{cval, own(func() { C.GDALDestroyTransformer(cval) })}
+	switch C.GoString(tree.pszValue) {
+	case "GenImgProjTransformer":
+		return GenImgProjTransformer{t}, nil
+	case "ReprojectionTransformer":
+		return ReprojectionTransformer{t}, nil
+	case "GCPTransformer":
+		return GCPTransformer{t}, nil
+	case "TPSTransformer":
+		return TPSTransformer{t}, nil
+	case "RPCTransformer":
+		return RPCTransformer{t}, nil
+	case "GeoLocTransformer":
+		return GeoLocTransformer{t}, nil
+	case "ApproxTransformer":
+		return ApproxTransformer{t}, nil
+	}
+	return t, nil
+}
+
+//Unimplemented: SimpleImageWarp
+//Unimplemented: SuggestedWarpOutput2
 //Unimplemented: TransformGeolocations
 
 /* --------------------------------------------- */
 /* Contour line functions                        */
 /* --------------------------------------------- */
 
-//Unimplemented: CreateContourGenerator
-//Unimplemented: FeedLine
-//Unimplemented: Destroy
-//Unimplemented: ContourWriter
-//Unimplemented: ContourGenerate
+// Options of RasterBand.Contour
+type ContourOptions struct {
+	// Distance between levels, ignored if Levels is set
+	Interval float64
+	// Level from which the other levels are a multiple of Interval away
+	Base float64
+	// Explicit levels
+	Levels []float64
+	// Whether pixels set to NoData are left out
+	UseNoData bool
+	NoData    float64
+	// Name of the field receiving the feature ID, empty for none
+	IDField string
+	// Name of the field receiving the level of lines, empty for none
+	ElevField string
+	// Generate polygons between consecutive levels rather than lines,
+	// which requires GDAL 2.4 or newer
+	Polygonize bool
+	// Names of the fields receiving the lower and upper levels of
+	// polygons, empty for none, only valid with Polygonize
+	ElevFieldMin string
+	ElevFieldMax string
+}
+
+// Generate contours of the band into layer, in the georeferenced
+// coordinates of the dataset
+func (src RasterBand) Contour(
+	layer Layer,
+	options ContourOptions,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(layer)
+	if len(options.Levels) == 0 && options.Interval <= 0 {
+		return newError(CPLE_IllegalArg, "Error: contour interval %v is not positive", options.Interval)
+	}
+	if !options.Polygonize && (options.ElevFieldMin != "" || options.ElevFieldMax != "") {
+		return newError(CPLE_IllegalArg, "Error: lower and upper level fields apply to contour polygons only")
+	}
+	fields, err := options.fields(layer)
+	if err != nil {
+		return err
+	}
+
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
+
+	if options.Polygonize {
+		opts := cStringList(options.list(fields))
+		defer C.CSLDestroy(opts)
+
+		defer captureErrors()()
+		cErr := C.goGDALContourGenerateEx(src.cval, unsafe.Pointer(layer.cval), opts, pf, pa)
+		return cplError(cErr)
+	}
+
+	var levels *C.double
+	if len(options.Levels) > 0 {
+		levels = (*C.double)(unsafe.Pointer(&options.Levels[0]))
+	}
+
+	defer captureErrors()()
+	cErr := C.GDALContourGenerate(
+		src.cval,
+		C.double(options.Interval),
+		C.double(options.Base),
+		C.int(len(options.Levels)),
+		levels,
+		BoolToCInt(options.UseNoData),
+		C.double(options.NoData),
+		unsafe.Pointer(layer.cval),
+		C.int(fields[0]),
+		C.int(fields[1]),
+		pf,
+		pa,
+	)
+	return cplError(cErr)
+}
+
+// Generate contours of the band into layer, stopping when ctx is done
+func (src RasterBand) ContourContext(
+	ctx context.Context,
+	layer Layer,
+	options ContourOptions,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	return runContext(ctx, progress, data, func(progress ProgressFunc) error {
+		return src.Contour(layer, options, progress, nil)
+	})
+}
+
+// Names of the options of GDALContourGenerateEx setting the fields, in the
+// order of ContourOptions.fields
+var contourFieldOptions = [4]string{"ID_FIELD", "ELEV_FIELD", "ELEV_FIELD_MIN", "ELEV_FIELD_MAX"}
+
+// Fetch the indexes in layer of the ID, level, lower and upper level
+// fields, -1 for those not set
+func (options ContourOptions) fields(layer Layer) ([4]int, error) {
+	names := [4]string{options.IDField, options.ElevField, options.ElevFieldMin, options.ElevFieldMax}
+	fields := [4]int{-1, -1, -1, -1}
+	for i, name := range names {
+		if name == "" {
+			continue
+		}
+		fields[i] = layer.Definition().FieldIndex(name)
+		if fields[i] < 0 {
+			return fields, newError(CPLE_IllegalArg, "Error: layer has no field %q", name)
+		}
+	}
+	return fields, nil
+}
+
+// Convert the options to those of GDALContourGenerateEx, given the
+// indexes of their fields
+func (options ContourOptions) list(fields [4]int) []string {
+	format := func(value float64) string {
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+
+	var list []string
+	if len(options.Levels) > 0 {
+		levels := make([]string, len(options.Levels))
+		for i, level := range options.Levels {
+			levels[i] = format(level)
+		}
+		list = append(list, "FIXED_LEVELS="+strings.Join(levels, ","))
+	} else {
+		list = append(list, "LEVEL_INTERVAL="+format(options.Interval), "LEVEL_BASE="+format(options.Base))
+	}
+	if options.UseNoData {
+		list = append(list, "NODATA="+format(options.NoData))
+	}
+	for i, index := range fields {
+		if index >= 0 {
+			list = append(list, fmt.Sprintf("%s=%d", contourFieldOptions[i], index))
+		}
+	}
+	if options.Polygonize {
+		list = append(list, "POLYGONIZE=YES")
+	}
+	return list
+}
+
+// Receives each contour line found by a ContourGenerator, in pixel
+// coordinates.  Returning an error stops the generation.
+type ContourWriter func(level float64, x, y []float64) error
+
+// Contour generator fed with scanlines one at a time, so that contours of
+// rasters that are not datasets, or too large for memory, can be computed
+type ContourGenerator struct {
+	cval   C.GDALContourGeneratorH
+	width  int
+	writer *contourWriter
+	owner  *owner
+}
+
+// Writer behind a cgo.Handle, recording the error that stopped it
+type contourWriter struct {
+	write ContourWriter
+	err   error
+}
+
+// Create a contour generator for a raster of the given size, passing the
+// lines at levels base + k*interval to writer as soon as they are complete
+func CreateContourGenerator(
+	width, height int,
+	useNoData bool,
+	noData, interval, base float64,
+	writer ContourWriter,
+) (ContourGenerator, error) {
+	if interval <= 0 {
+		return ContourGenerator{}, newError(CPLE_IllegalArg, "Error: contour interval %v is not positive", interval)
+	}
+
+	w := &contourWriter{write: writer}
+	handle := cgo.NewHandle(w)
+
+	defer captureErrors()()
+	cg := C.goGDALCreateContourGenerator(
+		C.int(width),
+		C.int(height),
+		BoolToCInt(useNoData),
+		C.double(noData),
+		C.double(interval),
+		C.double(base),
+		C.uintptr_t(handle),
+	)
+	if cg == nil {
+		handle.Delete()
+		return ContourGenerator{}, lastError(CPLE_AppDefined, "Error: cannot create contour generator")
+	}
+	return ContourGenerator{cg, width, w, own(func() {
+		C.GDAL_CG_Destroy(cg)
+		handle.Delete()
+	})}, nil
+}
+
+// Feed the next scanline, from top to bottom.  The error returned by the
+// writer, if any, is returned.
+func (cg ContourGenerator) FeedLine(scanline []float64) error {
+	defer runtime.KeepAlive(cg)
+	if !cg.owner.owned() {
+		return newError(CPLE_ObjectNull, "Error: contour generator is not valid")
+	}
+	if len(scanline) != cg.width {
+		return newError(CPLE_IllegalArg, "Error: got %d values for a scanline of %d", len(scanline), cg.width)
+	}
+
+	defer captureErrors()()
+	err := C.GDAL_CG_FeedLine(cg.cval, (*C.double)(unsafe.Pointer(&scanline[0])))
+	if writerErr := cg.writer.err; writerErr != nil {
+		cg.writer.err = nil
+		return writerErr
+	}
+	return cplError(err)
+}
+
+// Destroy the contour generator.  Destroying it twice does nothing.  GDAL
+// passes the lines left to the writer, whose errors are dropped, see Close.
+func (cg ContourGenerator) Destroy() {
+	cg.owner.release()
+}
+
+// Destroy the contour generator, returning the error of the writer while
+// GDAL passes it the lines left.  Closing it twice does nothing.
+func (cg ContourGenerator) Close() error {
+	cg.owner.release()
+	if cg.writer == nil {
+		return nil
+	}
+	err := cg.writer.err
+	cg.writer.err = nil
+	return err
+}
+
+//export goGDALContourWriter
+func goGDALContourWriter(level C.double, count C.int, x, y *C.double, handle C.uintptr_t) C.int {
+	w := cgo.Handle(handle).Value().(*contourWriter)
+	xs := make([]float64, int(count))
+	ys := make([]float64, int(count))
+	copy(xs, unsafe.Slice((*float64)(unsafe.Pointer(x)), int(count)))
+	copy(ys, unsafe.Slice((*float64)(unsafe.Pointer(y)), int(count)))
+	if err := w.write(float64(level), xs, ys); err != nil {
+		w.err = err
+		return C.CE_Failure
+	}
+	return C.CE_None
+}
 
 /* --------------------------------------------- */
 /* Rasterizer functions                          */
 /* --------------------------------------------- */
 
-// Burn geometries into raster
-//Unimplmemented: RasterizeGeometries
+// How burnt values combine with the pixels they are burnt into
+type MergeAlg int
+
+const (
+	// Replace the pixels with the burn value
+	MergeReplace = MergeAlg(iota)
+	// Add the burn value to the pixels, since GDAL 2.0
+	MergeAdd
+)
+
+// Options of the rasterizer functions.  The zero value burns the pixels
+// whose center is inside the geometries, replacing their value.
+type RasterizeOptions struct {
+	// Burn every pixel touched by the geometries
+	AllTouched bool
+	// How burn values combine with the existing pixels
+	MergeAlg MergeAlg
+	// Field of the layers holding the burn value of each feature, replacing
+	// the burn values passed to RasterizeLayers
+	Attribute string
+	// Burn the Z coordinate of the geometries, offset by the burn values
+	BurnValueFromZ bool
+	// Transformer from the coordinates of the geometries to pixels, or nil
+	// to use the geotransform of the raster
+	Transformer Transformer
+	// Other options, such as CHUNKYSIZE
+	Options []string
+}
 
-// Burn geometries from the specified list of layers into the raster
-//Unimplemented: RasterizeLayers
+// Convert the options to those of the GDAL rasterizer functions
+func (options *RasterizeOptions) list() []string {
+	if options == nil {
+		return nil
+	}
+	list := append([]string{}, options.Options...)
+	if options.AllTouched {
+		list = append(list, "ALL_TOUCHED=TRUE")
+	}
+	if options.MergeAlg == MergeAdd {
+		list = append(list, "MERGE_ALG=ADD")
+	}
+	if options.Attribute != "" {
+		list = append(list, "ATTRIBUTE="+options.Attribute)
+	}
+	if options.BurnValueFromZ {
+		list = append(list, "BURN_VALUE_FROM=Z")
+	}
+	return list
+}
 
-// Burn geometries from the specified list of layers into the raster
-//Unimplemented: RasterizeLayersBuf
+// Fetch the transformer function and argument of the options
+func (options *RasterizeOptions) transformer() (C.GDALTransformerFunc, unsafe.Pointer) {
+	defer runtime.KeepAlive(options)
+	if options == nil || options.Transformer == nil {
+		return nil, nil
+	}
+	return C.goGDALUseTransformer(), options.Transformer.handle().cval
+}
+
+// Burn geometries into the given bands of the dataset, numbered from 1.
+// burnValues holds a value per geometry and band, a value per geometry for
+// all bands, or a single value for everything.
+func (dataset Dataset) RasterizeGeometries(
+	bands []int,
+	geoms []Geometry,
+	burnValues []float64,
+	options *RasterizeOptions,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	defer runtime.KeepAlive(dataset)
+	defer runtime.KeepAlive(geoms)
+	defer runtime.KeepAlive(options)
+	if len(bands) == 0 || len(geoms) == 0 {
+		return newError(CPLE_IllegalArg, "Error: got %d bands and %d geometries to rasterize", len(bands), len(geoms))
+	}
+	values, err := expandBurnValues(burnValues, len(geoms), len(bands), "geometries")
+	if err != nil {
+		return err
+	}
+
+	cBands := make([]C.int, len(bands))
+	for i, band := range bands {
+		cBands[i] = C.int(band)
+	}
+	cGeoms := make([]C.OGRGeometryH, len(geoms))
+	for i, geom := range geoms {
+		cGeoms[i] = geom.cval
+	}
+	opts := cStringList(options.list())
+	defer C.CSLDestroy(opts)
+	pfnTransformer, pTransformArg := options.transformer()
+
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
+
+	defer captureErrors()()
+	cErr := C.GDALRasterizeGeometries(
+		dataset.cval,
+		C.int(len(bands)),
+		&cBands[0],
+		C.int(len(geoms)),
+		&cGeoms[0],
+		pfnTransformer,
+		pTransformArg,
+		(*C.double)(unsafe.Pointer(&values[0])),
+		opts,
+		pf,
+		pa,
+	)
+	return cplError(cErr)
+}
+
+// Burn the features of layers into the given bands of the dataset,
+// numbered from 1.  burnValues holds a value per layer and band, a value
+// per layer for all bands, or a single value for everything, and is
+// ignored if options name an Attribute.
+func (dataset Dataset) RasterizeLayers(
+	bands []int,
+	layers []Layer,
+	burnValues []float64,
+	options *RasterizeOptions,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	defer runtime.KeepAlive(dataset)
+	defer runtime.KeepAlive(layers)
+	defer runtime.KeepAlive(options)
+	if len(bands) == 0 || len(layers) == 0 {
+		return newError(CPLE_IllegalArg, "Error: got %d bands and %d layers to rasterize", len(bands), len(layers))
+	}
+	var cValues *C.double
+	if options == nil || options.Attribute == "" {
+		values, err := expandBurnValues(burnValues, len(layers), len(bands), "layers")
+		if err != nil {
+			return err
+		}
+		cValues = (*C.double)(unsafe.Pointer(&values[0]))
+	}
+
+	cBands := make([]C.int, len(bands))
+	for i, band := range bands {
+		cBands[i] = C.int(band)
+	}
+	cLayers := make([]C.OGRLayerH, len(layers))
+	for i, layer := range layers {
+		cLayers[i] = layer.cval
+	}
+	opts := cStringList(options.list())
+	defer C.CSLDestroy(opts)
+	pfnTransformer, pTransformArg := options.transformer()
+
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
+
+	defer captureErrors()()
+	cErr := C.GDALRasterizeLayers(
+		dataset.cval,
+		C.int(len(bands)),
+		&cBands[0],
+		C.int(len(layers)),
+		&cLayers[0],
+		pfnTransformer,
+		pTransformArg,
+		cValues,
+		opts,
+		pf,
+		pa,
+	)
+	return cplError(cErr)
+}
+
+// Burn the features of layers into a buffer of xSize x ySize pixels,
+// georeferenced by projection and geoTransform, without any dataset
+func RasterizeLayersBuf[T Pixel](
+	buffer []T,
+	xSize, ySize int,
+	layers []Layer,
+	projection string,
+	geoTransform GeoTransform,
+	burnValue float64,
+	options *RasterizeOptions,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	defer runtime.KeepAlive(layers)
+	defer runtime.KeepAlive(options)
+	if xSize <= 0 || ySize <= 0 || len(buffer) < xSize*ySize {
+		return newError(CPLE_IllegalArg, "Error: buffer of %d pixels is too small for %dx%d", len(buffer), xSize, ySize)
+	}
+	if len(layers) == 0 {
+		return newError(CPLE_IllegalArg, "Error: no layer to rasterize")
+	}
+	dataType, pData, _, err := sliceBuffer(buffer)
+	if err != nil {
+		return err
+	}
+	pixelSize := dataType.Size() / 8
+
+	cLayers := make([]C.OGRLayerH, len(layers))
+	for i, layer := range layers {
+		cLayers[i] = layer.cval
+	}
+	var cProjection *C.char
+	if projection != "" {
+		cProjection = C.CString(projection)
+		defer C.free(unsafe.Pointer(cProjection))
+	}
+	opts := cStringList(options.list())
+	defer C.CSLDestroy(opts)
+	pfnTransformer, pTransformArg := options.transformer()
+
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
+
+	defer captureErrors()()
+	cErr := C.GDALRasterizeLayersBuf(
+		pData,
+		C.int(xSize),
+		C.int(ySize),
+		C.GDALDataType(dataType),
+		C.int(pixelSize),
+		C.int(pixelSize*xSize),
+		C.int(len(layers)),
+		&cLayers[0],
+		cProjection,
+		(*C.double)(unsafe.Pointer(&geoTransform[0])),
+		pfnTransformer,
+		pTransformArg,
+		C.double(burnValue),
+		opts,
+		pf,
+		pa,
+	)
+	return cplError(cErr)
+}
+
+// Expand burn values given per item and band, per item, or once, to a
+// value per item and band
+func expandBurnValues(values []float64, items, bands int, name string) ([]float64, error) {
+	switch len(values) {
+	case items * bands:
+		return values, nil
+	case items, 1:
+		expanded := make([]float64, items*bands)
+		for i := range expanded {
+			if len(values) == 1 {
+				expanded[i] = values[0]
+			} else {
+				expanded[i] = values[i/bands]
+			}
+		}
+		return expanded, nil
+	}
+	return nil, newError(CPLE_IllegalArg, "Error: got %d burn values for %d bands and %d %s", len(values), bands, items, name)
+}
 
 /* --------------------------------------------- */
 /* Gridding functions                            */
 /* --------------------------------------------- */
 
-//Unimplemented: CreateGrid
+// Point with a value to grid
+type XYZ struct {
+	X, Y, Z float64
+}
+
+// Algorithm interpolating scattered points to a grid
+type GridAlgorithm int
+
+const (
+	GGA_InverseDistanceToAPower                = GridAlgorithm(C.GGA_InverseDistanceToAPower)
+	GGA_MovingAverage                          = GridAlgorithm(C.GGA_MovingAverage)
+	GGA_NearestNeighbor                        = GridAlgorithm(C.GGA_NearestNeighbor)
+	GGA_MetricMinimum                          = GridAlgorithm(C.GGA_MetricMinimum)
+	GGA_MetricMaximum                          = GridAlgorithm(C.GGA_MetricMaximum)
+	GGA_MetricRange                            = GridAlgorithm(C.GGA_MetricRange)
+	GGA_MetricCount                            = GridAlgorithm(C.GGA_MetricCount)
+	GGA_MetricAverageDistance                  = GridAlgorithm(C.GGA_MetricAverageDistance)
+	GGA_MetricAverageDistancePts               = GridAlgorithm(C.GGA_MetricAverageDistancePts)
+	GGA_Linear                                 = GridAlgorithm(C.GGA_Linear)
+	GGA_InverseDistanceToAPowerNearestNeighbor = GridAlgorithm(C.GGA_InverseDistanceToAPowerNearestNeighbor)
+)
+
+// Parameters of a gridding algorithm: GridInverseDistanceOptions,
+// GridInverseDistanceNearestNeighborOptions, GridMovingAverageOptions,
+// GridNearestNeighborOptions, GridDataMetricsOptions or GridLinearOptions.
+// The search ellipses are given by their two radii and the angle of their
+// first axis, counter clockwise in degrees; zero radii select all points.
+type GridOptions interface {
+	// Allocate the GDAL options of the algorithm, freed with VSIFree
+	cOptions(algorithm GridAlgorithm) (unsafe.Pointer, error)
+}
+
+// Parameters of GGA_InverseDistanceToAPower.  gdal_grid uses a Power of 2.
+type GridInverseDistanceOptions struct {
+	Power, Smoothing                 float64
+	AnisotropyRatio, AnisotropyAngle float64
+	Radius1, Radius2, Angle          float64
+	// Maximum number of points to use, or 0 for all of them
+	MaxPoints int
+	// Minimum number of points to find, or the node is NoDataValue
+	MinPoints   int
+	NoDataValue float64
+}
+
+func (o GridInverseDistanceOptions) cOptions(algorithm GridAlgorithm) (unsafe.Pointer, error) {
+	if err := checkGridOptions(algorithm, "inverse distance", o.MaxPoints, o.MinPoints, GGA_InverseDistanceToAPower); err != nil {
+		return nil, err
+	}
+	defer captureErrors()()
+	options := C.goGDALGridInverseDistanceOptions(
+		C.double(o.Power),
+		C.double(o.Smoothing),
+		C.double(o.AnisotropyRatio),
+		C.double(o.AnisotropyAngle),
+		C.double(o.Radius1),
+		C.double(o.Radius2),
+		C.double(o.Angle),
+		C.GUInt32(o.MaxPoints),
+		C.GUInt32(o.MinPoints),
+		C.double(o.NoDataValue),
+	)
+	return gridOptions(options)
+}
+
+// Parameters of GGA_InverseDistanceToAPowerNearestNeighbor, which searches
+// the points within a circle of Radius with a quad tree, since GDAL 2.1
+type GridInverseDistanceNearestNeighborOptions struct {
+	Power, Smoothing, Radius float64
+	// Maximum number of nearest points to use, or 0 for all of them
+	MaxPoints int
+	// Minimum number of points to find, or the node is NoDataValue
+	MinPoints   int
+	NoDataValue float64
+}
+
+func (o GridInverseDistanceNearestNeighborOptions) cOptions(algorithm GridAlgorithm) (unsafe.Pointer, error) {
+	if err := checkGridOptions(algorithm, "nearest neighbour inverse distance", o.MaxPoints, o.MinPoints, GGA_InverseDistanceToAPowerNearestNeighbor); err != nil {
+		return nil, err
+	}
+	defer captureErrors()()
+	options := C.goGDALGridInverseDistanceNNOptions(
+		C.double(o.Power),
+		C.double(o.Smoothing),
+		C.double(o.Radius),
+		C.GUInt32(o.MaxPoints),
+		C.GUInt32(o.MinPoints),
+		C.double(o.NoDataValue),
+	)
+	return gridOptions(options)
+}
+
+// Parameters of GGA_MovingAverage
+type GridMovingAverageOptions struct {
+	Radius1, Radius2, Angle float64
+	// Minimum number of points to find, or the node is NoDataValue
+	MinPoints   int
+	NoDataValue float64
+}
+
+func (o GridMovingAverageOptions) cOptions(algorithm GridAlgorithm) (unsafe.Pointer, error) {
+	if err := checkGridOptions(algorithm, "moving average", 0, o.MinPoints, GGA_MovingAverage); err != nil {
+		return nil, err
+	}
+	defer captureErrors()()
+	options := C.goGDALGridMovingAverageOptions(
+		C.double(o.Radius1),
+		C.double(o.Radius2),
+		C.double(o.Angle),
+		C.GUInt32(o.MinPoints),
+		C.double(o.NoDataValue),
+	)
+	return gridOptions(options)
+}
+
+// Parameters of GGA_NearestNeighbor.  Nodes without any point in their
+// search ellipse are NoDataValue.
+type GridNearestNeighborOptions struct {
+	Radius1, Radius2, Angle float64
+	NoDataValue             float64
+}
+
+func (o GridNearestNeighborOptions) cOptions(algorithm GridAlgorithm) (unsafe.Pointer, error) {
+	if err := checkGridOptions(algorithm, "nearest neighbour", 0, 0, GGA_NearestNeighbor); err != nil {
+		return nil, err
+	}
+	defer captureErrors()()
+	options := C.goGDALGridNearestNeighborOptions(
+		C.double(o.Radius1),
+		C.double(o.Radius2),
+		C.double(o.Angle),
+		C.double(o.NoDataValue),
+	)
+	return gridOptions(options)
+}
+
+// Parameters of the data metrics GGA_MetricMinimum, GGA_MetricMaximum,
+// GGA_MetricRange, GGA_MetricCount, GGA_MetricAverageDistance and
+// GGA_MetricAverageDistancePts, computed over the points of the search
+// ellipse of each node
+type GridDataMetricsOptions struct {
+	Radius1, Radius2, Angle float64
+	// Minimum number of points to find, or the node is NoDataValue
+	MinPoints   int
+	NoDataValue float64
+}
+
+func (o GridDataMetricsOptions) cOptions(algorithm GridAlgorithm) (unsafe.Pointer, error) {
+	if err := checkGridOptions(algorithm, "data metrics", 0, o.MinPoints,
+		GGA_MetricMinimum, GGA_MetricMaximum, GGA_MetricRange, GGA_MetricCount,
+		GGA_MetricAverageDistance, GGA_MetricAverageDistancePts); err != nil {
+		return nil, err
+	}
+	defer captureErrors()()
+	options := C.goGDALGridDataMetricsOptions(
+		C.double(o.Radius1),
+		C.double(o.Radius2),
+		C.double(o.Angle),
+		C.GUInt32(o.MinPoints),
+		C.double(o.NoDataValue),
+	)
+	return gridOptions(options)
+}
+
+// Parameters of GGA_Linear, interpolating within the triangles of a
+// Delaunay triangulation of the points, since GDAL 2.1.  Nodes outside the
+// triangulation take the value of the nearest point within Radius, -1 for
+// any distance, or are NoDataValue.
+type GridLinearOptions struct {
+	Radius      float64
+	NoDataValue float64
+}
+
+func (o GridLinearOptions) cOptions(algorithm GridAlgorithm) (unsafe.Pointer, error) {
+	if err := checkGridOptions(algorithm, "linear", 0, 0, GGA_Linear); err != nil {
+		return nil, err
+	}
+	defer captureErrors()()
+	options := C.goGDALGridLinearOptions(C.double(o.Radius), C.double(o.NoDataValue))
+	return gridOptions(options)
+}
+
+// Check that options apply to the algorithm and their point counts are valid
+func checkGridOptions(algorithm GridAlgorithm, name string, maxPoints, minPoints int, algorithms ...GridAlgorithm) error {
+	if maxPoints < 0 || minPoints < 0 {
+		return newError(CPLE_IllegalArg, "Error: invalid point counts %d and %d", maxPoints, minPoints)
+	}
+	for _, alg := range algorithms {
+		if alg == algorithm {
+			return nil
+		}
+	}
+	return newError(CPLE_IllegalArg, "Error: %s options do not apply to grid algorithm %d", name, algorithm)
+}
+
+func gridOptions(options unsafe.Pointer) (unsafe.Pointer, error) {
+	if options == nil {
+		return nil, lastError(CPLE_AppDefined, "Error: cannot create grid options")
+	}
+	return options, nil
+}
+
+// Interpolate scattered points to a grid of xSize x ySize nodes covering
+// extent.  The values are returned row by row from the top, MaxY, edge of
+// the extent, so that they match the geotransform
+//
+//	GeoTransform{extent.MinX(), (extent.MaxX() - extent.MinX()) / xSize, 0,
+//		extent.MaxY(), 0, (extent.MinY() - extent.MaxY()) / ySize}
+func Grid(
+	points []XYZ,
+	algorithm GridAlgorithm,
+	options GridOptions,
+	extent Envelope,
+	xSize, ySize int,
+	progress ProgressFunc,
+	data interface{},
+) ([]float32, error) {
+	if len(points) == 0 {
+		return nil, newError(CPLE_IllegalArg, "Error: no point to grid")
+	}
+	if xSize <= 0 || ySize <= 0 {
+		return nil, newError(CPLE_IllegalArg, "Error: invalid grid size %dx%d", xSize, ySize)
+	}
+	if options == nil {
+		return nil, newError(CPLE_IllegalArg, "Error: no options for grid algorithm %d", algorithm)
+	}
+	cOptions, err := options.cOptions(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	defer C.VSIFree(cOptions)
+
+	x := make([]float64, len(points))
+	y := make([]float64, len(points))
+	z := make([]float64, len(points))
+	for i, point := range points {
+		x[i], y[i], z[i] = point.X, point.Y, point.Z
+	}
+	values := make([]float32, xSize*ySize)
+
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
+
+	defer captureErrors()()
+	cErr := C.GDALGridCreate(
+		C.GDALGridAlgorithm(algorithm),
+		cOptions,
+		C.GUInt32(len(points)),
+		(*C.double)(unsafe.Pointer(&x[0])),
+		(*C.double)(unsafe.Pointer(&y[0])),
+		(*C.double)(unsafe.Pointer(&z[0])),
+		C.double(extent.MinX()),
+		C.double(extent.MaxX()),
+		// GDAL fills the grid from its yMin edge
+		C.double(extent.MaxY()),
+		C.double(extent.MinY()),
+		C.GUInt32(xSize),
+		C.GUInt32(ySize),
+		C.GDT_Float32,
+		unsafe.Pointer(&values[0]),
+		pf,
+		pa,
+	)
+	if err := cplError(cErr); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Interpolate the vertices of the features of layer to a grid, as Grid
+// does.  The values are those of the zField field of the features, skipping
+// features where it is not set, or the Z coordinates if zField is empty.
+func GridLayer(
+	layer Layer,
+	zField string,
+	algorithm GridAlgorithm,
+	options GridOptions,
+	extent Envelope,
+	xSize, ySize int,
+	progress ProgressFunc,
+	data interface{},
+) ([]float32, error) {
+	points, err := layerPoints(layer, zField)
+	if err != nil {
+		return nil, err
+	}
+	return Grid(points, algorithm, options, extent, xSize, ySize, progress, data)
+}
+
+// Read the vertices of the features of a layer with their values
+func layerPoints(layer Layer, zField string) ([]XYZ, error) {
+	field := -1
+	if zField != "" {
+		field = layer.Definition().FieldIndex(zField)
+		if field < 0 {
+			return nil, newError(CPLE_IllegalArg, "Error: layer has no field %q", zField)
+		}
+	}
+
+	var points []XYZ
+	layer.ResetReading()
+	for {
+		feature, ok := layer.NextFeature()
+		if !ok {
+			break
+		}
+		geom, ok := feature.Geometry()
+		if ok && (field < 0 || feature.IsFieldSet(field)) {
+			var value float64
+			if field >= 0 {
+				value = feature.FieldAsFloat64(field)
+			}
+			points = appendGeometryPoints(points, geom, field >= 0, value)
+		}
+		feature.Destroy()
+	}
+	return points, nil
+}
+
+// Append the vertices of a geometry, with value in place of their Z
+// coordinate if useValue is set
+func appendGeometryPoints(points []XYZ, geom Geometry, useValue bool, value float64) []XYZ {
+	for i := 0; i < geom.GeometryCount(); i++ {
+		points = appendGeometryPoints(points, geom.Geometry(i), useValue, value)
+	}
+	for i := 0; i < geom.PointCount(); i++ {
+		x, y, z := geom.Point(i)
+		if useValue {
+			z = value
+		}
+		points = append(points, XYZ{x, y, z})
+	}
+	return points
+}
+
 //Unimplemented: ComputeMatchingPoints
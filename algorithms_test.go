@@ -0,0 +1,813 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gdal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Create an in-memory raster with a single band holding a 16x16 square of
+// ones in the middle of zeros, georeferenced in WGS84
+func createSquareRaster(t *testing.T, dataType DataType) Dataset {
+	dataset := createMemoryRaster(t, 32, 32, dataType)
+	data := make([]uint8, 32*32)
+	for y := 8; y < 24; y++ {
+		for x := 8; x < 24; x++ {
+			data[y*32+x] = 1
+		}
+	}
+	if err := WriteWindow(dataset.RasterBand(1), 0, 0, 32, 32, data); err != nil {
+		t.Fatal(err)
+	}
+
+	srs := CreateSpatialReference("")
+	defer srs.Destroy()
+	if err := srs.FromEPSG(4326); err != nil {
+		t.Fatal(err)
+	}
+	wkt, err := srs.ToWKT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dataset.SetProjection(wkt); err != nil {
+		t.Fatal(err)
+	}
+	if err := dataset.SetGeoTransform(GeoTransform{10, 0.1, 0, 50, 0, -0.1}); err != nil {
+		t.Fatal(err)
+	}
+	return dataset
+}
+
+// Check that the algorithm wrappers return a nil error on success
+func TestAlgorithmsNilOnSuccess(t *testing.T) {
+	src := createSquareRaster(t, Byte)
+	defer src.Close()
+	band := src.RasterBand(1)
+
+	dst := createSquareRaster(t, Byte)
+	defer dst.Close()
+
+	ds, _, feature := createMemoryLayer(t)
+	defer ds.Destroy()
+	defer feature.Destroy()
+	layer := createFieldsLayer(t, ds, "polygons", GT_Polygon, "value")
+
+	ct := CreateColorTable(PI_RGB)
+	defer ct.Destroy()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"ComputeMedianCutPCT", func() error { return ComputeMedianCutPCT(band, band, band, 2, ct, nil, nil) }},
+		{"DitherRGB2PCT", func() error { return DitherRGB2PCT(band, band, band, dst.RasterBand(1), ct, nil, nil) }},
+		{"ComputeProximity", func() error {
+			return band.ComputeProximity(dst.RasterBand(1), []string{"VALUES=1"}, nil, nil)
+		}},
+		{"FillNoData", func() error { return dst.RasterBand(1).FillNoData(band, 10, 0, nil, nil, nil) }},
+		{"Polygonize", func() error { return band.Polygonize(RasterBand{}, layer, 0, nil, nil, nil) }},
+		{"FPolygonize", func() error { return band.FPolygonize(RasterBand{}, layer, 0, nil, nil, nil) }},
+		{"SieveFilter", func() error {
+			return band.SieveFilter(RasterBand{}, dst.RasterBand(1), 4, 4, nil, nil, nil)
+		}},
+		{"ReprojectImage", func() error {
+			return src.ReprojectImage("", dst, "", GRA_NearestNeighbour, 0, 0, nil, nil, nil)
+		}},
+	}
+	for _, test := range tests {
+		if err := test.call(); err != nil {
+			t.Errorf("%s: got %v, want nil", test.name, err)
+		}
+	}
+}
+
+// Run each algorithm with a progress callback, checking that the callback is
+// called with the user data and that returning 0 interrupts the algorithm
+func TestAlgorithmProgress(t *testing.T) {
+	src := createSquareRaster(t, Byte)
+	defer src.Close()
+	band := src.RasterBand(1)
+
+	dst := createSquareRaster(t, Byte)
+	defer dst.Close()
+
+	ds, _, feature := createMemoryLayer(t)
+	defer ds.Destroy()
+	defer feature.Destroy()
+	layer := ds.CreateLayer("polygons", SpatialReference{}, GT_Polygon, nil)
+	fd := CreateFieldDefinition("value", FT_Integer)
+	defer fd.Destroy()
+	if err := layer.CreateField(fd, false); err != nil {
+		t.Fatalf("CreateField: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		run  func(progress ProgressFunc, data interface{}) error
+	}{
+		{"ComputeMedianCutPCT", func(progress ProgressFunc, data interface{}) error {
+			ct := CreateColorTable(PI_RGB)
+			defer ct.Destroy()
+			return ComputeMedianCutPCT(band, band, band, 2, ct, progress, data)
+		}},
+		{"DitherRGB2PCT", func(progress ProgressFunc, data interface{}) error {
+			ct := CreateColorTable(PI_RGB)
+			defer ct.Destroy()
+			if err := ComputeMedianCutPCT(band, band, band, 2, ct, nil, nil); err != nil {
+				return err
+			}
+			return DitherRGB2PCT(band, band, band, dst.RasterBand(1), ct, progress, data)
+		}},
+		{"ComputeProximity", func(progress ProgressFunc, data interface{}) error {
+			return band.ComputeProximity(dst.RasterBand(1), []string{"VALUES=1"}, progress, data)
+		}},
+		{"FillNoData", func(progress ProgressFunc, data interface{}) error {
+			return dst.RasterBand(1).FillNoData(band, 10, 0, nil, progress, data)
+		}},
+		{"Polygonize", func(progress ProgressFunc, data interface{}) error {
+			return band.Polygonize(RasterBand{}, layer, 0, nil, progress, data)
+		}},
+		{"FPolygonize", func(progress ProgressFunc, data interface{}) error {
+			return band.FPolygonize(RasterBand{}, layer, 0, nil, progress, data)
+		}},
+		{"SieveFilter", func(progress ProgressFunc, data interface{}) error {
+			return band.SieveFilter(RasterBand{}, dst.RasterBand(1), 4, 4, nil, progress, data)
+		}},
+		{"Contour", func(progress ProgressFunc, data interface{}) error {
+			options := ContourOptions{Interval: 0.5}
+			return band.Contour(layer, options, progress, data)
+		}},
+		{"ReprojectImage", func(progress ProgressFunc, data interface{}) error {
+			return src.ReprojectImage("", dst, "", GRA_NearestNeighbour, 0, 0, progress, data, nil)
+		}},
+	}
+
+	for _, test := range tests {
+		calls := 0
+		err := test.run(func(complete float64, message string, data interface{}) int {
+			if data != test.name {
+				t.Errorf("%s: got data %v, want %q", test.name, data, test.name)
+			}
+			calls++
+			return 1
+		}, test.name)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+		if calls == 0 {
+			t.Errorf("%s: progress callback not called", test.name)
+		}
+
+		err = test.run(func(complete float64, message string, data interface{}) int {
+			return 0
+		}, nil)
+		if err == nil {
+			t.Errorf("%s: not interrupted by progress callback", test.name)
+		}
+	}
+}
+
+func TestConcurrentProgress(t *testing.T) {
+	const workers = 8
+	var srcs, dsts [workers]Dataset
+	for i := range srcs {
+		srcs[i] = createSquareRaster(t, Byte)
+		defer srcs[i].Close()
+		dsts[i] = createMemoryRaster(t, 32, 32, Float32)
+		defer dsts[i].Close()
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			calls := 0
+			progress := func(complete float64, message string, data interface{}) int {
+				if data != i {
+					t.Errorf("goroutine %d: got data %v", i, data)
+				}
+				calls++
+				return 1
+			}
+			err := srcs[i].RasterBand(1).ComputeProximity(dsts[i].RasterBand(1), nil, progress, i)
+			if err != nil {
+				t.Errorf("goroutine %d: %v", i, err)
+			}
+			if calls == 0 {
+				t.Errorf("goroutine %d: progress callback not called", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// Check that transformer maps each of the points from to the matching
+// point of to, within tolerance
+func checkTransform(t *testing.T, name string, transformer Transformer, dstToSrc bool, from, to [][2]float64, tolerance float64) {
+	t.Helper()
+	x := make([]float64, len(from))
+	y := make([]float64, len(from))
+	for i, p := range from {
+		x[i], y[i] = p[0], p[1]
+	}
+	ok, err := transformer.Transform(dstToSrc, x, y, nil)
+	if err != nil {
+		t.Errorf("%s: Transform: %v", name, err)
+		return
+	}
+	for i, p := range to {
+		if !ok[i] || math.Abs(x[i]-p[0]) > tolerance || math.Abs(y[i]-p[1]) > tolerance {
+			t.Errorf("%s: point %v transformed to (%v, %v, %v), want %v", name, from[i], x[i], y[i], ok[i], p)
+		}
+	}
+}
+
+func TestTransformers(t *testing.T) {
+	src := createSquareRaster(t, Byte)
+	defer src.Close()
+
+	pixels := [][2]float64{{0, 0}, {32, 32}, {5, 5}}
+	coords := [][2]float64{{10, 50}, {13.2, 46.8}, {10.5, 49.5}}
+
+	genImgProj, err := CreateGenImgProjTransformer2(src, Dataset{}, nil)
+	if err != nil {
+		t.Fatalf("CreateGenImgProjTransformer2: %v", err)
+	}
+	defer genImgProj.Destroy()
+	checkTransform(t, "GenImgProj", genImgProj, false, pixels, coords, 1e-9)
+	checkTransform(t, "GenImgProj reversed", genImgProj, true, coords, pixels, 1e-9)
+
+	xSize, ySize, geoTransform, err := SuggestedWarpOutput(src, genImgProj)
+	if err != nil || xSize != 32 || ySize != 32 {
+		t.Errorf("SuggestedWarpOutput: got %dx%d, %v", xSize, ySize, err)
+	}
+	for i, want := range (GeoTransform{10, 0.1, 0, 50, 0, -0.1}) {
+		if math.Abs(geoTransform[i]-want) > 1e-6 {
+			t.Errorf("SuggestedWarpOutput: got geotransform %v", geoTransform)
+			break
+		}
+	}
+
+	xml, err := SerializeTransformer(genImgProj)
+	if err != nil {
+		t.Fatalf("SerializeTransformer: %v", err)
+	}
+	restored, err := DeserializeTransformer(xml)
+	if err != nil {
+		t.Fatalf("DeserializeTransformer: %v", err)
+	}
+	defer restored.Destroy()
+	if _, ok := restored.(GenImgProjTransformer); !ok {
+		t.Errorf("DeserializeTransformer: got %T, want GenImgProjTransformer", restored)
+	}
+	checkTransform(t, "deserialized GenImgProj", restored, false, pixels, coords, 1e-9)
+	if _, err := DeserializeTransformer("<NotATransformer/>"); err == nil {
+		t.Error("DeserializeTransformer of unknown XML: got no error")
+	}
+
+	gcps := []GCP{
+		{ID: "1", Pixel: 0, Line: 0, X: 10, Y: 50},
+		{ID: "2", Pixel: 10, Line: 0, X: 11, Y: 50},
+		{ID: "3", Pixel: 0, Line: 10, X: 10, Y: 49},
+		{ID: "4", Pixel: 10, Line: 10, X: 11, Y: 49},
+	}
+	gcp, err := CreateGCPTransformer(gcps, 1, false)
+	if err != nil {
+		t.Fatalf("CreateGCPTransformer: %v", err)
+	}
+	checkTransform(t, "GCP", gcp, false, pixels, coords, 1e-6)
+	tps, err := CreateTPSTransformer(gcps, false)
+	if err != nil {
+		t.Fatalf("CreateTPSTransformer: %v", err)
+	}
+	defer tps.Destroy()
+	checkTransform(t, "TPS", tps, false, pixels, coords, 1e-6)
+
+	approx, err := CreateApproxTransformer(gcp, 0.125)
+	if err != nil {
+		t.Fatalf("CreateApproxTransformer: %v", err)
+	}
+	checkTransform(t, "Approx", approx, false, pixels, coords, 1e-6)
+	approx.Destroy()
+	// the approximating transformer destroyed gcp
+	gcp.Destroy()
+	if _, err := CreateApproxTransformer(gcp, 0.125); err == nil {
+		t.Error("CreateApproxTransformer of destroyed transformer: got no error")
+	}
+
+	wgs84, mercator := srsWKT(t, wgs84Proj4), srsWKT(t, webMercatorProj4)
+	reprojection, err := CreateReprojectionTransformer(wgs84, mercator)
+	if err != nil {
+		t.Fatalf("CreateReprojectionTransformer: %v", err)
+	}
+	defer reprojection.Destroy()
+	checkTransform(t, "Reprojection", reprojection, false, [][2]float64{{180, 0}}, [][2]float64{{webMercatorMaxX, 0}}, 1e-3)
+
+	if _, err := CreateRPCTransformer(map[string]string{"LINE_OFF": "1"}, false, 0.1, nil); err == nil {
+		t.Error("CreateRPCTransformer with incomplete RPCs: got no error")
+	}
+	if _, err := CreateGeoLocTransformer(src, nil, false); err == nil {
+		t.Error("CreateGeoLocTransformer without geolocation arrays: got no error")
+	}
+	if _, err := (transformer{}).Transform(false, []float64{0}, []float64{0}, nil); err == nil {
+		t.Error("Transform with invalid transformer: got no error")
+	}
+}
+
+// Convert a PROJ.4 definition to WKT
+func srsWKT(t *testing.T, proj4 string) string {
+	srs := CreateSpatialReference("")
+	defer srs.Destroy()
+	if err := srs.FromProj4(proj4); err != nil {
+		t.Fatal(err)
+	}
+	wkt, err := srs.ToWKT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wkt
+}
+
+func TestWarp(t *testing.T) {
+	src := createSquareRaster(t, Byte)
+	defer src.Close()
+
+	// a square of twos 1.6 degrees east of the square of ones
+	shifted := createSquareRaster(t, Byte)
+	defer shifted.Close()
+	twos := make([]uint8, 16*16)
+	for i := range twos {
+		twos[i] = 2
+	}
+	if err := WriteWindow(shifted.RasterBand(1), 8, 8, 16, 16, twos); err != nil {
+		t.Fatal(err)
+	}
+	if err := shifted.SetGeoTransform(GeoTransform{11.6, 0.1, 0, 50, 0, -0.1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the sources start at pixel (10, 10) and (26, 10) of the destination
+	createDestination := func() Dataset {
+		dst := createMemoryRaster(t, 64, 64, Byte)
+		if err := dst.SetProjection(src.Projection()); err != nil {
+			t.Fatal(err)
+		}
+		if err := dst.SetGeoTransform(GeoTransform{9, 0.1, 0, 51, 0, -0.1}); err != nil {
+			t.Fatal(err)
+		}
+		return dst
+	}
+
+	cutline, err := CreateFromWKT("POLYGON ((9 44, 11.6 44, 11.6 52, 9 52, 9 44))", SpatialReference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cutline.Destroy()
+
+	tests := []struct {
+		name    string
+		srcs    []Dataset
+		options *WarpOptions
+		pixels  map[[2]int]uint8
+	}{
+		{"nodata", []Dataset{src}, &WarpOptions{DstNoData: []float64{255}, InitDest: "NO_DATA", NumThreads: -1},
+			map[[2]int]uint8{{0, 0}: 255, {12, 12}: 0, {25, 25}: 1}},
+		{"mosaic", []Dataset{src, shifted}, &WarpOptions{SrcNoData: []float64{0}, InitDest: "0", MaxError: 0.125},
+			map[[2]int]uint8{{0, 0}: 0, {25, 25}: 1, {30, 25}: 1, {40, 25}: 2}},
+		{"cutline", []Dataset{src}, &WarpOptions{Cutline: cutline, InitDest: "0"},
+			map[[2]int]uint8{{20, 25}: 1, {30, 25}: 0}},
+		{"band map", []Dataset{src}, &WarpOptions{SrcBands: []int{1}, DstBands: []int{1}, ResampleAlg: GRA_Mode},
+			map[[2]int]uint8{{25, 25}: 1}},
+		{"no progress", []Dataset{src}, nil, map[[2]int]uint8{{25, 25}: 1}},
+	}
+	for _, test := range tests {
+		dst := createDestination()
+		calls := 0
+		var progress ProgressFunc
+		if test.name != "no progress" {
+			progress = func(complete float64, message string, data interface{}) int {
+				calls++
+				return 1
+			}
+		}
+		if err := Warp(test.srcs, dst, test.options, progress, nil); err != nil {
+			t.Errorf("%s: Warp: %v", test.name, err)
+			dst.Close()
+			continue
+		}
+		if progress != nil && calls == 0 {
+			t.Errorf("%s: progress callback not called", test.name)
+		}
+		data, err := ReadWindow[uint8](dst.RasterBand(1), 0, 0, 64, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for pixel, want := range test.pixels {
+			if got := data[pixel[1]*64+pixel[0]]; got != want {
+				t.Errorf("%s: pixel %v: got %d, want %d", test.name, pixel, got, want)
+			}
+		}
+		dst.Close()
+	}
+
+	dst := createDestination()
+	defer dst.Close()
+	if err := Warp([]Dataset{src}, dst, &WarpOptions{DstBands: []int{1, 1}}, nil, nil); err == nil {
+		t.Error("Warp with mismatched band maps: got no error")
+	}
+	if err := Warp(nil, dst, nil, nil, nil); err == nil {
+		t.Error("Warp without sources: got no error")
+	}
+	if err := src.ReprojectImage("", dst, "", GRA_Bilinear, 0, 0, nil, nil, &WarpOptions{Cutline: cutline}); err == nil {
+		t.Error("ReprojectImage with a cutline: got no error")
+	}
+	err = src.ReprojectImage("", dst, "", GRA_NearestNeighbour, 0, 0, nil, nil, &WarpOptions{ResampleAlg: GRA_Bilinear})
+	if !errors.Is(err, CPLE_IllegalArg) {
+		t.Errorf("ReprojectImage with conflicting resampling: got %v, want illegal argument", err)
+	}
+	err = src.ReprojectImage("", dst, "", GRA_NearestNeighbour, 1<<20, 0, nil, nil, &WarpOptions{MemoryLimit: 1 << 24})
+	if !errors.Is(err, CPLE_IllegalArg) {
+		t.Errorf("ReprojectImage with conflicting memory limit: got %v, want illegal argument", err)
+	}
+
+	// without bands in the options, GDAL leaves out pixels that the alpha
+	// band of the source makes transparent
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rgba := driver.Create("", 32, 32, 2, Byte, nil)
+	defer rgba.Close()
+	if err := rgba.SetGeoTransform(GeoTransform{10, 0.1, 0, 50, 0, -0.1}); err != nil {
+		t.Fatal(err)
+	}
+	alpha := make([]uint8, 32*32)
+	for i := range alpha {
+		if i%32 >= 16 {
+			alpha[i] = 255
+		}
+	}
+	if err := WriteWindow(rgba.RasterBand(1), 0, 0, 32, 32, bytes.Repeat([]uint8{5}, 32*32)); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteWindow(rgba.RasterBand(2), 0, 0, 32, 32, alpha); err != nil {
+		t.Fatal(err)
+	}
+	rgbaDst := driver.Create("", 32, 32, 2, Byte, nil)
+	defer rgbaDst.Close()
+	if err := rgbaDst.SetGeoTransform(GeoTransform{10, 0.1, 0, 50, 0, -0.1}); err != nil {
+		t.Fatal(err)
+	}
+	for _, ds := range []Dataset{rgba, rgbaDst} {
+		if err := ds.RasterBand(2).SetColorInterp(CI_AlphaBand); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rgba.ReprojectImage("", rgbaDst, "", GRA_NearestNeighbour, 0, 0, nil, nil, &WarpOptions{}); err != nil {
+		t.Fatalf("ReprojectImage with alpha band: %v", err)
+	}
+	data, err := ReadWindow[uint8](rgbaDst.RasterBand(1), 0, 0, 32, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data[0] != 0 || data[31] != 5 {
+		t.Errorf("ReprojectImage with alpha band: got %d and %d, want 0 and 5", data[0], data[31])
+	}
+}
+
+// Create a layer of the data source with the given real or integer fields
+func createFieldsLayer(t *testing.T, ds DataSource, name string, geomType GeometryType, fields ...string) Layer {
+	layer := ds.CreateLayer(name, SpatialReference{}, geomType, nil)
+	for _, field := range fields {
+		fieldType := FT_Real
+		if field == "id" {
+			fieldType = FT_Integer
+		}
+		fd := CreateFieldDefinition(field, fieldType)
+		err := layer.CreateField(fd, false)
+		fd.Destroy()
+		if err != nil {
+			t.Fatalf("CreateField %s: %v", field, err)
+		}
+	}
+	return layer
+}
+
+func TestContour(t *testing.T) {
+	src := createSquareRaster(t, Float32)
+	defer src.Close()
+	band := src.RasterBand(1)
+
+	ds, _, feature := createMemoryLayer(t)
+	defer ds.Destroy()
+	defer feature.Destroy()
+
+	lines := createFieldsLayer(t, ds, "lines", GT_LineString, "id", "elev")
+	err := band.Contour(lines, ContourOptions{Levels: []float64{0.5}, IDField: "id", ElevField: "elev"}, nil, nil)
+	if err != nil {
+		t.Fatalf("Contour: %v", err)
+	}
+	if count, _ := lines.FeatureCount(true); count != 1 {
+		t.Errorf("Contour: got %d lines, want 1", count)
+	}
+	lines.ResetReading()
+	if line, ok := lines.NextFeature(); ok {
+		if elev := line.FieldAsFloat64(1); elev != 0.5 {
+			t.Errorf("Contour: got level %v, want 0.5", elev)
+		}
+		// the line follows the edges of the 1.6 degrees wide square, with
+		// its corners cut
+		if geom, ok := line.Geometry(); !ok || math.Abs(geom.Length()-4*1.6) > 0.1 {
+			t.Errorf("Contour: got line of length %v", geom.Length())
+		}
+		line.Destroy()
+	}
+
+	// nodata pixels are left out, so that only ones remain: no level crossed
+	nodata := createFieldsLayer(t, ds, "nodata", GT_LineString, "id", "elev")
+	err = band.Contour(nodata, ContourOptions{Interval: 10, Base: 0.5, UseNoData: true, NoData: 0}, nil, nil)
+	if count, _ := nodata.FeatureCount(true); err != nil || count != 0 {
+		t.Errorf("Contour with nodata: got %d lines, %v", count, err)
+	}
+
+	// the zero value sets no field
+	unset := createFieldsLayer(t, ds, "unset", GT_LineString, "id", "elev")
+	if err := band.Contour(unset, ContourOptions{Levels: []float64{0.5}}, nil, nil); err != nil {
+		t.Fatalf("Contour without fields: %v", err)
+	}
+	unset.ResetReading()
+	if line, ok := unset.NextFeature(); !ok {
+		t.Error("Contour without fields: got no line")
+	} else {
+		if line.IsFieldSet(0) || line.IsFieldSet(1) {
+			t.Errorf("Contour without fields: got id %v and level %v set", line.FieldAsFloat64(0), line.FieldAsFloat64(1))
+		}
+		line.Destroy()
+	}
+	for _, option := range (ContourOptions{Interval: 1}).list([4]int{-1, -1, -1, -1}) {
+		if strings.Contains(option, "_FIELD") {
+			t.Errorf("options of the zero value: got %s", option)
+		}
+	}
+
+	if err := band.Contour(lines, ContourOptions{}, nil, nil); err == nil {
+		t.Error("Contour without interval or levels: got no error")
+	}
+	if err := band.Contour(lines, ContourOptions{Interval: 1, ElevField: "missing"}, nil, nil); !errors.Is(err, CPLE_IllegalArg) {
+		t.Errorf("Contour with missing field: got %v, want illegal argument", err)
+	}
+	if err := band.Contour(lines, ContourOptions{Interval: 1, ElevFieldMin: "elev"}, nil, nil); !errors.Is(err, CPLE_IllegalArg) {
+		t.Errorf("Contour lines with lower level field: got %v, want illegal argument", err)
+	}
+
+	polygons := createFieldsLayer(t, ds, "polygons", GT_Polygon, "id", "min", "max")
+	options := ContourOptions{Levels: []float64{0.5}, Polygonize: true, IDField: "id", ElevFieldMin: "min", ElevFieldMax: "max"}
+	err = band.Contour(polygons, options, nil, nil)
+	if VERSION_NUM < 2040000 {
+		if err == nil {
+			t.Error("Contour polygons before GDAL 2.4: got no error")
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("Contour polygons: %v", err)
+	}
+	if count, _ := polygons.FeatureCount(true); count == 0 {
+		t.Error("Contour polygons: got no polygon")
+	}
+}
+
+func TestContourGenerator(t *testing.T) {
+	var levels []float64
+	closed := true
+	cg, err := CreateContourGenerator(32, 32, false, 0, 1, 0.5, func(level float64, x, y []float64) error {
+		levels = append(levels, level)
+		closed = closed && len(x) > 2 && x[0] == x[len(x)-1] && y[0] == y[len(y)-1]
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("CreateContourGenerator: %v", err)
+	}
+	defer cg.Destroy()
+
+	for y := 0; y < 32; y++ {
+		line := make([]float64, 32)
+		for x := 8; x < 24 && y >= 8 && y < 24; x++ {
+			line[x] = 1
+		}
+		if err := cg.FeedLine(line); err != nil {
+			t.Fatalf("FeedLine %d: %v", y, err)
+		}
+	}
+	if len(levels) != 1 || levels[0] != 0.5 || !closed {
+		t.Errorf("ContourGenerator: got levels %v, closed %v, want one closed line at 0.5", levels, closed)
+	}
+	if err := cg.FeedLine(make([]float64, 3)); err == nil {
+		t.Error("FeedLine of wrong width: got no error")
+	}
+
+	stop := errors.New("stop")
+	cg, err = CreateContourGenerator(2, 2, false, 0, 1, 0.5, func(level float64, x, y []float64) error {
+		return stop
+	})
+	if err != nil {
+		t.Fatalf("CreateContourGenerator: %v", err)
+	}
+	// the writer fails while fed or while GDAL flushes the lines left
+	err = cg.FeedLine([]float64{0, 1})
+	if err == nil {
+		err = cg.FeedLine([]float64{1, 0})
+	}
+	if closeErr := cg.Close(); err == nil {
+		err = closeErr
+	}
+	if !errors.Is(err, stop) {
+		t.Errorf("ContourGenerator with failing writer: got %v, want the writer error", err)
+	}
+	if err := cg.Close(); err != nil {
+		t.Errorf("second Close: got %v", err)
+	}
+}
+
+func TestRasterize(t *testing.T) {
+	dst := createMemoryRaster(t, 32, 32, Byte)
+	defer dst.Close()
+	geoTransform := GeoTransform{10, 0.1, 0, 50, 0, -0.1}
+	if err := dst.SetGeoTransform(geoTransform); err != nil {
+		t.Fatal(err)
+	}
+
+	// pixels 0-9 and 10-19 of the first ten lines, and a sliver of pixel
+	// (0, 29) that covers no pixel center
+	wkts := []string{
+		"POLYGON ((10 49, 11 49, 11 50, 10 50, 10 49))",
+		"POLYGON ((11 49, 12 49, 12 50, 11 50, 11 49))",
+		"POLYGON ((10.01 47.01, 10.04 47.01, 10.04 47.04, 10.01 47.04, 10.01 47.01))",
+	}
+	geoms := make([]Geometry, len(wkts))
+	for i, wkt := range wkts {
+		geom, err := CreateFromWKT(wkt, SpatialReference{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer geom.Destroy()
+		geoms[i] = geom
+	}
+
+	checkPixels := func(name string, data []uint8, pixels map[[2]int]uint8) {
+		t.Helper()
+		for pixel, want := range pixels {
+			if got := data[pixel[1]*32+pixel[0]]; got != want {
+				t.Errorf("%s: pixel %v: got %d, want %d", name, pixel, got, want)
+			}
+		}
+	}
+	readPixels := func() []uint8 {
+		data, err := ReadWindow[uint8](dst.RasterBand(1), 0, 0, 32, 32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	if err := dst.RasterizeGeometries([]int{1}, geoms, []float64{5, 7, 9}, nil, nil, nil); err != nil {
+		t.Fatalf("RasterizeGeometries: %v", err)
+	}
+	checkPixels("RasterizeGeometries", readPixels(), map[[2]int]uint8{{5, 5}: 5, {15, 5}: 7, {25, 25}: 0, {0, 29}: 0})
+
+	options := &RasterizeOptions{AllTouched: true, MergeAlg: MergeAdd}
+	if err := dst.RasterizeGeometries([]int{1}, geoms[0:1], []float64{1}, options, nil, nil); err != nil {
+		t.Fatalf("RasterizeGeometries with MERGE_ALG=ADD: %v", err)
+	}
+	if err := dst.RasterizeGeometries([]int{1}, geoms[2:], []float64{9}, options, nil, nil); err != nil {
+		t.Fatalf("RasterizeGeometries with ALL_TOUCHED: %v", err)
+	}
+	checkPixels("RasterizeGeometries options", readPixels(), map[[2]int]uint8{{5, 5}: 6, {15, 5}: 7, {0, 29}: 9})
+
+	if err := dst.RasterizeGeometries([]int{1}, geoms, []float64{1, 2}, nil, nil, nil); err == nil {
+		t.Error("RasterizeGeometries with mismatched burn values: got no error")
+	}
+
+	ds, _, feature := createMemoryLayer(t)
+	defer ds.Destroy()
+	defer feature.Destroy()
+	layer := createFieldsLayer(t, ds, "polygons", GT_Polygon, "value")
+	for i, value := range []float64{20, 30} {
+		feature := layer.Definition().Create()
+		if err := feature.SetGeometry(geoms[i]); err != nil {
+			t.Fatal(err)
+		}
+		feature.SetFieldFloat64(0, value)
+		if err := layer.Create(feature); err != nil {
+			t.Fatal(err)
+		}
+		feature.Destroy()
+	}
+
+	err := dst.RasterizeLayers([]int{1}, []Layer{layer}, nil, &RasterizeOptions{Attribute: "value"}, nil, nil)
+	if err != nil {
+		t.Fatalf("RasterizeLayers: %v", err)
+	}
+	checkPixels("RasterizeLayers", readPixels(), map[[2]int]uint8{{5, 5}: 20, {15, 5}: 30, {25, 25}: 0})
+
+	calls := 0
+	progress := func(complete float64, message string, data interface{}) int {
+		calls++
+		return 1
+	}
+	buffer := make([]float32, 32*32)
+	err = RasterizeLayersBuf(buffer, 32, 32, []Layer{layer}, "", geoTransform, 3, nil, progress, nil)
+	if err != nil {
+		t.Fatalf("RasterizeLayersBuf: %v", err)
+	}
+	if buffer[5*32+5] != 3 || buffer[5*32+15] != 3 || buffer[25*32+25] != 0 {
+		t.Errorf("RasterizeLayersBuf: got %v, %v, %v", buffer[5*32+5], buffer[5*32+15], buffer[25*32+25])
+	}
+	if calls == 0 {
+		t.Error("RasterizeLayersBuf: progress callback not called")
+	}
+	if err := RasterizeLayersBuf(buffer[:10], 32, 32, []Layer{layer}, "", geoTransform, 3, nil, nil, nil); err == nil {
+		t.Error("RasterizeLayersBuf with short buffer: got no error")
+	}
+}
+
+func TestGrid(t *testing.T) {
+	// a 4x2 grid of unit cells, with points on the centers of the top left
+	// and bottom right ones
+	var extent Envelope
+	extent.SetMinX(0)
+	extent.SetMaxX(4)
+	extent.SetMinY(0)
+	extent.SetMaxY(2)
+	points := []XYZ{{0.5, 1.5, 1}, {3.5, 0.5, 9}}
+
+	tests := []struct {
+		name          string
+		algorithm     GridAlgorithm
+		options       GridOptions
+		topLeft, last float32
+	}{
+		{"nearest neighbour", GGA_NearestNeighbor, GridNearestNeighborOptions{}, 1, 9},
+		{"inverse distance", GGA_InverseDistanceToAPower, GridInverseDistanceOptions{Power: 2}, 1, 9},
+		{"moving average", GGA_MovingAverage, GridMovingAverageOptions{Radius1: 1, Radius2: 1, NoDataValue: -1}, 1, 9},
+		{"count", GGA_MetricCount, GridDataMetricsOptions{Radius1: 1, Radius2: 1}, 1, 1},
+		{"range", GGA_MetricRange, GridDataMetricsOptions{Radius1: 1, Radius2: 1, MinPoints: 2, NoDataValue: -1}, -1, -1},
+	}
+	for _, test := range tests {
+		values, err := Grid(points, test.algorithm, test.options, extent, 4, 2, nil, nil)
+		if err != nil {
+			t.Errorf("Grid %s: %v", test.name, err)
+			continue
+		}
+		if len(values) != 8 || values[0] != test.topLeft || values[7] != test.last {
+			t.Errorf("Grid %s: got %v, want %v at the top left and %v at the bottom right", test.name, values, test.topLeft, test.last)
+		}
+	}
+
+	if _, err := Grid(points, GGA_NearestNeighbor, GridMovingAverageOptions{}, extent, 4, 2, nil, nil); err == nil {
+		t.Error("Grid with options of another algorithm: got no error")
+	}
+	if _, err := Grid(points, GGA_MetricCount, GridDataMetricsOptions{MinPoints: -1}, extent, 4, 2, nil, nil); err == nil {
+		t.Error("Grid with negative point count: got no error")
+	}
+	if _, err := Grid(nil, GGA_NearestNeighbor, GridNearestNeighborOptions{}, extent, 4, 2, nil, nil); err == nil {
+		t.Error("Grid without points: got no error")
+	}
+
+	ds, _, feature := createMemoryLayer(t)
+	defer ds.Destroy()
+	defer feature.Destroy()
+	layer := createFieldsLayer(t, ds, "stations", GT_Point, "elev")
+	for _, point := range points {
+		feature := layer.Definition().Create()
+		geom, err := CreateFromWKT(fmt.Sprintf("POINT (%v %v)", point.X, point.Y), SpatialReference{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := feature.SetGeometryDirectly(geom); err != nil {
+			t.Fatal(err)
+		}
+		feature.SetFieldFloat64(0, point.Z)
+		if err := layer.Create(feature); err != nil {
+			t.Fatal(err)
+		}
+		feature.Destroy()
+	}
+
+	values, err := GridLayer(layer, "elev", GGA_NearestNeighbor, GridNearestNeighborOptions{}, extent, 4, 2, nil, nil)
+	if err != nil || len(values) != 8 || values[0] != 1 || values[7] != 9 {
+		t.Errorf("GridLayer: got %v, %v", values, err)
+	}
+	if _, err := GridLayer(layer, "missing", GGA_NearestNeighbor, GridNearestNeighborOptions{}, extent, 4, 2, nil, nil); err == nil {
+		t.Error("GridLayer with missing field: got no error")
+	}
+}
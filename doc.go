@@ -3,11 +3,15 @@ Package gdal provides a wrapper for GDAL, the Geospatial Data Abstraction Librar
 
 Limitations
 
-Some less oftenly used functions are not yet implemented.  The majority of these involve style tables, asynchronous I/O, and GCPs.
+Some less oftenly used functions are not yet implemented.  The majority of these involve style tables and asynchronous I/O.
 
 The documentation is fairly limited, but the functionality fairly closely matches that of the C++ api.
 
-This wrapper has only been tested on 64-bit Ubuntu Linux, with version 1.9.1 of the GDAL library.
+Memory management
+
+Handles returned by constructors such as Open, CreateSpatialReference or Layer.NextFeature own their C object, which is freed by Close or Destroy; releasing a handle more than once is harmless.  Handles fetched from another object, such as Layer.Definition or Feature.Geometry, borrow it and are never freed by Close or Destroy.  Calling SetFinalizers(true) lets the garbage collector free owned objects that were not released explicitly.
+
+This wrapper requires version 1.10 or newer of the GDAL library, which is located with pkg-config.  Build with the gdal_static tag to link GDAL statically.
 
 Usage
 
@@ -17,7 +21,7 @@ A simple program to create a georeferenced blank 256x256 GeoTIFF:
 	import (
 		"fmt"
 		"flag"
-		gdal "github.com/lukeroth/gdal_go"
+		"github.com/kikht/gdal"
 	)
 
 	func main() {
@@ -42,7 +46,7 @@ A simple program to create a georeferenced blank 256x256 GeoTIFF:
 		spatialRef.FromEPSG(3857)
 		srString, err := spatialRef.ToWKT()
 		dataset.SetProjection(srString)
-		dataset.SetGeoTransform([]float64{444720, 30, 0, 3751320, 0, -30})
+		dataset.SetGeoTransform(gdal.GeoTransform{444720, 30, 0, 3751320, 0, -30})
 		raster := dataset.RasterBand(1)
 		raster.IO(gdal.Write, 0, 0, 256, 256, buffer, 256, 256, 0, 0)
 	}
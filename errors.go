@@ -0,0 +1,257 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+*/
+import "C"
+import (
+	"fmt"
+	"runtime"
+)
+
+func init() {
+	C.goGDALInstallErrorHandler()
+}
+
+/* -------------------------------------------------------------------- */
+/*      Error classes and numbers.                                      */
+/* -------------------------------------------------------------------- */
+
+// Severity of an error reported by CPLError
+type ErrorClass int
+
+const (
+	CE_None    = ErrorClass(C.CE_None)
+	CE_Debug   = ErrorClass(C.CE_Debug)
+	CE_Warning = ErrorClass(C.CE_Warning)
+	CE_Failure = ErrorClass(C.CE_Failure)
+	CE_Fatal   = ErrorClass(C.CE_Fatal)
+)
+
+func (class ErrorClass) String() string {
+	switch class {
+	case CE_None:
+		return "None"
+	case CE_Debug:
+		return "Debug"
+	case CE_Warning:
+		return "Warning"
+	case CE_Failure:
+		return "Failure"
+	case CE_Fatal:
+		return "Fatal"
+	}
+	return fmt.Sprintf("ErrorClass(%d)", int(class))
+}
+
+// Error number reported by CPLError.  An ErrorNum is itself an error, so it
+// can be used as the target of errors.Is:
+//
+//	if errors.Is(err, gdal.CPLE_OpenFailed) { ... }
+type ErrorNum int
+
+const (
+	CPLE_None            = ErrorNum(C.CPLE_None)
+	CPLE_AppDefined      = ErrorNum(C.CPLE_AppDefined)
+	CPLE_OutOfMemory     = ErrorNum(C.CPLE_OutOfMemory)
+	CPLE_FileIO          = ErrorNum(C.CPLE_FileIO)
+	CPLE_OpenFailed      = ErrorNum(C.CPLE_OpenFailed)
+	CPLE_IllegalArg      = ErrorNum(C.CPLE_IllegalArg)
+	CPLE_NotSupported    = ErrorNum(C.CPLE_NotSupported)
+	CPLE_AssertionFailed = ErrorNum(C.CPLE_AssertionFailed)
+	CPLE_NoWriteAccess   = ErrorNum(C.CPLE_NoWriteAccess)
+	CPLE_UserInterrupt   = ErrorNum(C.CPLE_UserInterrupt)
+	CPLE_ObjectNull      = ErrorNum(C.CPLE_ObjectNull)
+)
+
+func (num ErrorNum) Error() string {
+	switch num {
+	case CPLE_None:
+		return "no error"
+	case CPLE_AppDefined:
+		return "application defined error"
+	case CPLE_OutOfMemory:
+		return "out of memory"
+	case CPLE_FileIO:
+		return "file I/O error"
+	case CPLE_OpenFailed:
+		return "open failed"
+	case CPLE_IllegalArg:
+		return "illegal argument"
+	case CPLE_NotSupported:
+		return "not supported"
+	case CPLE_AssertionFailed:
+		return "assertion failed"
+	case CPLE_NoWriteAccess:
+		return "no write access"
+	case CPLE_UserInterrupt:
+		return "user interrupt"
+	case CPLE_ObjectNull:
+		return "object is null"
+	}
+	return fmt.Sprintf("CPL error %d", int(num))
+}
+
+// Error code returned by OGR functions.  Like ErrorNum, an OGRErr is itself
+// an error and can be used as the target of errors.Is:
+//
+//	if errors.Is(err, gdal.OGRERR_CORRUPT_DATA) { ... }
+type OGRErr int
+
+const (
+	OGRERR_NONE                      = OGRErr(C.OGRERR_NONE)
+	OGRERR_NOT_ENOUGH_DATA           = OGRErr(C.OGRERR_NOT_ENOUGH_DATA)
+	OGRERR_NOT_ENOUGH_MEMORY         = OGRErr(C.OGRERR_NOT_ENOUGH_MEMORY)
+	OGRERR_UNSUPPORTED_GEOMETRY_TYPE = OGRErr(C.OGRERR_UNSUPPORTED_GEOMETRY_TYPE)
+	OGRERR_UNSUPPORTED_OPERATION     = OGRErr(C.OGRERR_UNSUPPORTED_OPERATION)
+	OGRERR_CORRUPT_DATA              = OGRErr(C.OGRERR_CORRUPT_DATA)
+	OGRERR_FAILURE                   = OGRErr(C.OGRERR_FAILURE)
+	OGRERR_UNSUPPORTED_SRS           = OGRErr(C.OGRERR_UNSUPPORTED_SRS)
+	OGRERR_INVALID_HANDLE            = OGRErr(C.OGRERR_INVALID_HANDLE)
+	OGRERR_NON_EXISTING_FEATURE      = OGRErr(C.OGRERR_NON_EXISTING_FEATURE)
+)
+
+func (err OGRErr) Error() string {
+	switch err {
+	case OGRERR_NONE:
+		return "no error"
+	case OGRERR_NOT_ENOUGH_DATA:
+		return "not enough data"
+	case OGRERR_NOT_ENOUGH_MEMORY:
+		return "not enough memory"
+	case OGRERR_UNSUPPORTED_GEOMETRY_TYPE:
+		return "unsupported geometry type"
+	case OGRERR_UNSUPPORTED_OPERATION:
+		return "unsupported operation"
+	case OGRERR_CORRUPT_DATA:
+		return "corrupt data"
+	case OGRERR_FAILURE:
+		return "failure"
+	case OGRERR_UNSUPPORTED_SRS:
+		return "unsupported SRS"
+	case OGRERR_INVALID_HANDLE:
+		return "invalid handle"
+	case OGRERR_NON_EXISTING_FEATURE:
+		return "non existing feature"
+	}
+	return fmt.Sprintf("OGR error %d", int(err))
+}
+
+/* -------------------------------------------------------------------- */
+/*      Error type.                                                     */
+/* -------------------------------------------------------------------- */
+
+// Error is returned by every function of this package that fails.  It
+// carries the class, number and message of the CPLError raised by GDAL
+// while serving the call, and the OGRErr code for OGR functions.
+type Error struct {
+	Class  ErrorClass
+	Num    ErrorNum
+	OGRErr OGRErr
+	Msg    string
+}
+
+func (err *Error) Error() string {
+	switch {
+	case err.Msg != "":
+		return err.Msg
+	case err.OGRErr != OGRERR_NONE:
+		return err.OGRErr.Error()
+	}
+	return err.Num.Error()
+}
+
+// Unwrap returns the error number and the OGR code, so that errors.Is
+// matches on either of them
+func (err *Error) Unwrap() []error {
+	if err.OGRErr == OGRERR_NONE {
+		return []error{err.Num}
+	}
+	return []error{err.Num, err.OGRErr}
+}
+
+// InterruptedError is returned by the Context variants of long operations,
+// such as ReprojectImageContext, when their context is done before they
+// complete.  It matches the context error with errors.Is, as well as the
+// error GDAL returned, usually CPLE_UserInterrupt.
+type InterruptedError struct {
+	// progress reached when the operation stopped, from 0 to 1
+	Complete float64
+	// context.Canceled or context.DeadlineExceeded
+	Context error
+	// error returned by the operation, nil if it did not start
+	Err error
+}
+
+func (err *InterruptedError) Error() string {
+	return fmt.Sprintf("interrupted at %.0f%%: %v", err.Complete*100, err.Context)
+}
+
+// Unwrap returns the context error and the error of the operation
+func (err *InterruptedError) Unwrap() []error {
+	if err.Err == nil {
+		return []error{err.Context}
+	}
+	return []error{err.Context, err.Err}
+}
+
+/* -------------------------------------------------------------------- */
+/*      Helper functions.                                               */
+/* -------------------------------------------------------------------- */
+
+// Pin the calling goroutine to its OS thread and clear the thread's error
+// state, so that errors raised by the following C calls belong to this
+// goroutine.  The returned function releases the thread; typical use is
+//
+//	defer captureErrors()()
+func captureErrors() func() {
+	runtime.LockOSThread()
+	C.goGDALErrorReset()
+	return runtime.UnlockOSThread
+}
+
+// Return the error recorded for the calling thread since captureErrors,
+// falling back to the given number and message if GDAL raised none.
+func lastError(num ErrorNum, format string, args ...interface{}) error {
+	if err := pendingError(); err != nil {
+		return err
+	}
+	return newError(num, format, args...)
+}
+
+// Return the error recorded for the calling thread since captureErrors, or
+// nil if GDAL raised none.  This tells failures apart from empty results of
+// functions that return NULL in both cases.
+func pendingError() error {
+	var cNum C.int
+	var cMsg *C.char
+	class := ErrorClass(C.goGDALLastError(&cNum, &cMsg))
+	if class < CE_Failure {
+		return nil
+	}
+	return &Error{class, ErrorNum(cNum), OGRERR_NONE, C.GoString(cMsg)}
+}
+
+// Create an error that did not originate from CPLError
+func newError(num ErrorNum, format string, args ...interface{}) error {
+	return &Error{CE_Failure, num, OGRERR_NONE, fmt.Sprintf(format, args...)}
+}
+
+// Convert a CPLErr return code, returning nil on success
+func cplError(err C.CPLErr) error {
+	if err < C.CE_Failure {
+		return nil
+	}
+	return lastError(CPLE_AppDefined, "%v error", ErrorClass(err))
+}
+
+// Convert an OGRErr return code, returning nil on success
+func ogrError(err C.OGRErr) error {
+	if err == C.OGRERR_NONE {
+		return nil
+	}
+	code := OGRErr(err)
+	e := lastError(CPLE_AppDefined, "%v", code).(*Error)
+	e.OGRErr = code
+	return e
+}
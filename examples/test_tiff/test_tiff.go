@@ -3,7 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
-	"github.com/foobaz/gdal"
+	"github.com/kikht/gdal"
 )
 
 func main() {
@@ -64,7 +64,11 @@ func main() {
 	raster.IO(gdal.Write, 0, 0, 256, 256, buffer, 256, 256, 0, 0)
 
 	fmt.Printf("Reading geotransform:")
-	geoTransform := dataset.GeoTransform()
+	geoTransform, ok := dataset.GeoTransform()
+	if !ok {
+		fmt.Printf("Dataset has no geotransform\n")
+		return
+	}
 	fmt.Printf("%v, %v, %v, %v, %v, %v\n",
 		geoTransform[0], geoTransform[1], geoTransform[2], geoTransform[3], geoTransform[4], geoTransform[5])
 
@@ -5,15 +5,19 @@ package gdal
 #include "gdal_version.h"
 #include "go_gdal.h"
 
-#cgo linux  CFLAGS: -I/usr/include/gdal
-#cgo linux  LDFLAGS: -lgdal
-#cgo darwin pkg-config: gdal
+#cgo !windows,!gdal_static pkg-config: gdal
+#cgo !windows,gdal_static pkg-config: --static gdal
+#cgo !windows,gdal_static LDFLAGS: -lstdc++
 #cgo windows LDFLAGS: -lgdal.dll
 */
 import "C"
 import (
+	"context"
 	"fmt"
-	"reflect"
+	"runtime"
+	"runtime/cgo"
+	"sort"
+	"strings"
 	"unsafe"
 )
 
@@ -37,39 +41,6 @@ const (
 	RELEASE_NAME  = string(C.GDAL_RELEASE_NAME)
 )
 
-// Error handling.  The following is bare-bones, and needs to be replaced with something more useful.
-func (err _Ctype_CPLErr) Error() string {
-	switch err {
-	case 0:
-		return "No Error"
-	case 1:
-		return "Debug Error"
-	case 2:
-		return "Warning Error"
-	case 3:
-		return "Failure Error"
-	case 4:
-		return "Fatal Error"
-	}
-	return "Illegal error"
-}
-
-func (err _Ctype_OGRErr) Error() string {
-	switch err {
-	case 0:
-		return "No Error"
-	case 1:
-		return "Debug Error"
-	case 2:
-		return "Warning Error"
-	case 3:
-		return "Failure Error"
-	case 4:
-		return "Fatal Error"
-	}
-	return "Illegal error"
-}
-
 // Pixel data types
 type DataType int
 
@@ -86,6 +57,12 @@ const (
 	CInt32   = DataType(C.GDT_CInt32)
 	CFloat32 = DataType(C.GDT_CFloat32)
 	CFloat64 = DataType(C.GDT_CFloat64)
+
+	// Only available with GDAL 3.5 (Int64, UInt64) and 3.7 (Int8) or
+	// later; equal to Unknown with older versions.
+	Int8   = DataType(C.GDT_Int8)
+	Int64  = DataType(C.GDT_Int64)
+	UInt64 = DataType(C.GDT_UInt64)
 )
 
 // Get data type size in bits.
@@ -215,11 +192,13 @@ type MajorObject struct {
 }
 
 type Dataset struct {
-	cval C.GDALDatasetH
+	cval  C.GDALDatasetH
+	owner *owner
 }
 
 type RasterBand struct {
-	cval C.GDALRasterBandH
+	cval   C.GDALRasterBandH
+	parent *owner
 }
 
 type Driver struct {
@@ -227,11 +206,37 @@ type Driver struct {
 }
 
 type ColorTable struct {
-	cval C.GDALColorTableH
+	cval  C.GDALColorTableH
+	owner *owner
 }
 
 type RasterAttributeTable struct {
-	cval C.GDALRasterAttributeTableH
+	cval  C.GDALRasterAttributeTableH
+	owner *owner
+}
+
+// Wrap a dataset handle owned by the caller
+func ownDataset(cval C.GDALDatasetH) Dataset {
+	if cval == nil {
+		return Dataset{}
+	}
+	return Dataset{cval, own(func() { C.GDALClose(cval) })}
+}
+
+// Wrap a color table handle owned by the caller
+func ownColorTable(cval C.GDALColorTableH) ColorTable {
+	if cval == nil {
+		return ColorTable{}
+	}
+	return ColorTable{cval, own(func() { C.GDALDestroyColorTable(cval) })}
+}
+
+// Wrap a raster attribute table handle owned by the caller
+func ownRasterAttributeTable(cval C.GDALRasterAttributeTableH) RasterAttributeTable {
+	if cval == nil {
+		return RasterAttributeTable{}
+	}
+	return RasterAttributeTable{cval, own(func() { C.GDALDestroyRasterAttributeTable(cval) })}
 }
 
 type AsyncReader struct {
@@ -246,8 +251,13 @@ type ColorEntry struct {
 /*      Callback "progress" function.                                   */
 /* -------------------------------------------------------------------- */
 
+// Callback reporting the progress of a long operation.  Complete runs from
+// 0 to 1; returning 0 asks the operation to stop, which then fails with
+// CPLE_UserInterrupt.  ProgressArg is the data value passed along with the
+// callback.
 type ProgressFunc func(complete float64, message string, progressArg interface{}) int
 
+// Progress callback that does nothing and never interrupts
 func DummyProgress(complete float64, message string, data interface{}) int {
 	msg := C.CString(message)
 	defer C.free(unsafe.Pointer(msg))
@@ -256,6 +266,9 @@ func DummyProgress(complete float64, message string, data interface{}) int {
 	return int(retval)
 }
 
+// Progress callback printing "0...10...20..." to stdout.  Like its C
+// counterpart it ignores message and data; see TerminalProgress to print to
+// another writer.
 func TermProgress(complete float64, message string, data interface{}) int {
 	msg := C.CString(message)
 	defer C.free(unsafe.Pointer(msg))
@@ -264,21 +277,31 @@ func TermProgress(complete float64, message string, data interface{}) int {
 	return int(retval)
 }
 
+// Progress callback forwarding to the scaled progress passed as data, which
+// must be the result of CreateScaledProgress
 func ScaledProgress(complete float64, message string, data interface{}) int {
-	msg := C.CString(message)
-	defer C.free(unsafe.Pointer(msg))
-
-	retval := C.GDALScaledProgress(C.double(complete), msg, unsafe.Pointer(nil))
-	return int(retval)
+	scaled, ok := data.(ProgressFunc)
+	if !ok || scaled == nil {
+		return 1
+	}
+	return scaled(complete, message, nil)
 }
 
-func CreateScaledProgress(min, max float64, progress ProgressFunc, data unsafe.Pointer) unsafe.Pointer {
-	panic("not implemented!")
-	return nil
+// Create a progress callback reporting to progress the range from min to
+// max of a longer operation.  Scaled callbacks can be nested, and the
+// result can be passed to any function taking a ProgressFunc, with nil data.
+func CreateScaledProgress(min, max float64, progress ProgressFunc, data interface{}) ProgressFunc {
+	return func(complete float64, message string, _ interface{}) int {
+		if progress == nil {
+			return 1
+		}
+		return progress(min+complete*(max-min), message, data)
+	}
 }
 
-func DestroyScaledProgress(data unsafe.Pointer) {
-	C.GDALDestroyScaledProgress(data)
+// Deprecated: callbacks created by CreateScaledProgress hold no C resources,
+// so there is nothing to destroy.
+func DestroyScaledProgress(data interface{}) {
 }
 
 // -----------------------------------------------------------------------
@@ -288,12 +311,23 @@ type goGDALProgressFuncProxyArgs struct {
 	data          interface{}
 }
 
+// Prepare a Go progress callback for a C function.  Go pointers must not be
+// kept by C, so the callback travels through C as a cgo.Handle.  The
+// returned function releases the handle, once the C function returned.
+func progressProxy(progress ProgressFunc, data interface{}) (C.GDALProgressFunc, unsafe.Pointer, func()) {
+	if progress == nil {
+		return nil, nil, func() {}
+	}
+	handle := cgo.NewHandle(goGDALProgressFuncProxyArgs{progress, data})
+	return C.goGDALProgressFuncProxyB(), C.goGDALHandleArg(C.uintptr_t(handle)), handle.Delete
+}
+
 //export goGDALProgressFuncProxyA
-func goGDALProgressFuncProxyA(complete C.double, message *C.char, data *interface{}) int {
-	if arg, ok := (*data).(goGDALProgressFuncProxyArgs); ok {
-		return arg.progresssFunc(
+func goGDALProgressFuncProxyA(complete C.double, message *C.char, handle C.uintptr_t) C.int {
+	if arg, ok := cgo.Handle(handle).Value().(goGDALProgressFuncProxyArgs); ok {
+		return C.int(arg.progresssFunc(
 			float64(complete), C.GoString(message), arg.data,
-		)
+		))
 	}
 	return 0
 }
@@ -340,7 +374,7 @@ func (driver Driver) Create(
 		C.GDALDataType(dataType),
 		(**C.char)(unsafe.Pointer(&opts[0])),
 	)
-	return Dataset{h}
+	return ownDataset(h)
 }
 
 // Create a copy of a dataset
@@ -352,6 +386,38 @@ func (driver Driver) CreateCopy(
 	progress ProgressFunc,
 	data interface{},
 ) Dataset {
+	dataset, _ := driver.createCopy(filename, sourceDataset, strict, options, progress, data)
+	return dataset
+}
+
+// Create a copy of a dataset, stopping when ctx is done
+func (driver Driver) CreateCopyContext(
+	ctx context.Context,
+	filename string,
+	sourceDataset Dataset,
+	strict int,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) (Dataset, error) {
+	var dataset Dataset
+	err := runContext(ctx, progress, data, func(progress ProgressFunc) error {
+		var err error
+		dataset, err = driver.createCopy(filename, sourceDataset, strict, options, progress, nil)
+		return err
+	})
+	return dataset, err
+}
+
+func (driver Driver) createCopy(
+	filename string,
+	sourceDataset Dataset,
+	strict int,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) (Dataset, error) {
+	defer runtime.KeepAlive(sourceDataset)
 	name := C.CString(filename)
 	defer C.free(unsafe.Pointer(name))
 
@@ -365,29 +431,23 @@ func (driver Driver) CreateCopy(
 
 	var h C.GDALDatasetH
 
-	if progress == nil {
-		h = C.GDALCreateCopy(
-			driver.cval, name,
-			sourceDataset.cval,
-			C.int(strict),
-			(**C.char)(unsafe.Pointer(&opts[0])),
-			nil,
-			nil,
-		)
-	} else {
-		arg := &goGDALProgressFuncProxyArgs{
-			progress, data,
-		}
-		h = C.GDALCreateCopy(
-			driver.cval, name,
-			sourceDataset.cval,
-			C.int(strict), (**C.char)(unsafe.Pointer(&opts[0])),
-			C.goGDALProgressFuncProxyB(),
-			unsafe.Pointer(arg),
-		)
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
+
+	defer captureErrors()()
+	h = C.GDALCreateCopy(
+		driver.cval, name,
+		sourceDataset.cval,
+		C.int(strict),
+		(**C.char)(unsafe.Pointer(&opts[0])),
+		pf,
+		pa,
+	)
+	if h == nil {
+		return Dataset{}, lastError(CPLE_AppDefined, "Error: failed to copy %s to %s", sourceDataset.Description(), filename)
 	}
 
-	return Dataset{h}
+	return ownDataset(h), nil
 }
 
 // Return the driver needed to access the provided dataset name.
@@ -412,11 +472,12 @@ func Open(filename string, access Access) (Dataset, error) {
 	cFilename := C.CString(filename)
 	defer C.free(unsafe.Pointer(cFilename))
 
+	defer captureErrors()()
 	dataset := C.GDALOpen(cFilename, C.GDALAccess(access))
 	if dataset == nil {
-		return Dataset{nil}, fmt.Errorf("Error: dataset '%s' open error", filename)
+		return Dataset{}, lastError(CPLE_OpenFailed, "Error: dataset '%s' open error", filename)
 	}
-	return Dataset{dataset}, nil
+	return ownDataset(dataset), nil
 }
 
 // Open a shared existing dataset
@@ -425,7 +486,7 @@ func OpenShared(filename string, access Access) Dataset {
 	defer C.free(unsafe.Pointer(cFilename))
 
 	dataset := C.GDALOpenShared(cFilename, C.GDALAccess(access))
-	return Dataset{dataset}
+	return ownDataset(dataset)
 }
 
 // Unimplemented: DumpOpenDatasets
@@ -437,7 +498,7 @@ func GetDriverByName(driverName string) (Driver, error) {
 
 	driver := C.GDALGetDriverByName(cName)
 	if driver == nil {
-		return Driver{driver}, fmt.Errorf("Error: driver '%s' not found", driverName)
+		return Driver{driver}, newError(CPLE_IllegalArg, "Error: driver '%s' not found", driverName)
 	}
 	return Driver{driver}, nil
 }
@@ -470,6 +531,18 @@ func (driver Driver) Deregister() {
 	C.GDALDeregisterDriver(driver.cval)
 }
 
+// Fetch the short name of the driver, such as "GTiff"
+func (driver Driver) ShortName() string {
+	name := C.GDALGetDriverShortName(driver.cval)
+	return C.GoString(name)
+}
+
+// Fetch the long name of the driver, such as "GeoTIFF"
+func (driver Driver) LongName() string {
+	name := C.GDALGetDriverLongName(driver.cval)
+	return C.GoString(name)
+}
+
 // Destroy the driver manager
 func DestroyDriverManager() {
 	C.GDALDestroyDriverManager()
@@ -480,9 +553,10 @@ func (driver Driver) DeleteDataset(name string) error {
 	cDriver := driver.cval
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	err := C.GDALDeleteDataset(cDriver, cName)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -495,9 +569,10 @@ func (driver Driver) RenameDataset(newName, oldName string) error {
 	defer C.free(unsafe.Pointer(cNewName))
 	cOldName := C.CString(oldName)
 	defer C.free(unsafe.Pointer(cOldName))
+	defer captureErrors()()
 	err := C.GDALRenameDataset(cDriver, cNewName, cOldName)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -510,9 +585,10 @@ func (driver Driver) CopyDatasetFiles(newName, oldName string) error {
 	defer C.free(unsafe.Pointer(cNewName))
 	cOldName := C.CString(oldName)
 	defer C.free(unsafe.Pointer(cOldName))
+	defer captureErrors()()
 	err := C.GDALCopyDatasetFiles(cDriver, cNewName, cOldName)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -522,12 +598,89 @@ func (driver Driver) CopyDatasetFiles(newName, oldName string) error {
 /*      GDAL_GCP                                                        */
 /* ==================================================================== */
 
-// Unimplemented: InitGCPs
-// Unimplemented: DeinitGCPs
-// Unimplemented: DuplicateGCPs
-// Unimplemented: GCPsToGeoTransform
-// Unimplemented: InvGeoTransform
-// Unimplemented: ApplyGeoTransform
+// Ground control point, tying a pixel/line position of a raster to a
+// georeferenced position.  The zero value matches the result of InitGCPs.
+type GCP struct {
+	ID    string
+	Info  string
+	Pixel float64
+	Line  float64
+	X     float64
+	Y     float64
+	Z     float64
+}
+
+// InitGCPs, DeinitGCPs and DuplicateGCPs are not needed: GCPs are plain Go
+// values, copied to and from C memory by the functions using them.
+
+// Fit an affine transformation to the GCPs.  Unless approxOK is set, the
+// fit fails if any GCP deviates from it by more than a quarter pixel.
+func GCPsToGeoTransform(gcps []GCP, approxOK bool) (GeoTransform, error) {
+	var transform GeoTransform
+	cGCPs, free := cGCPList(gcps)
+	defer free()
+
+	defer captureErrors()()
+	ok := C.GDALGCPsToGeoTransform(
+		C.int(len(gcps)),
+		cGCPs,
+		(*C.double)(unsafe.Pointer(&transform[0])),
+		BoolToCInt(approxOK),
+	)
+	if ok == 0 {
+		return transform, lastError(CPLE_AppDefined, "Error: no geotransform fits the %d GCPs", len(gcps))
+	}
+	return transform, nil
+}
+
+// Copy a C array of GCPs to Go
+func goGCPList(count C.int, cGCPs *C.GDAL_GCP) []GCP {
+	if count <= 0 || cGCPs == nil {
+		return nil
+	}
+	list := unsafe.Slice(cGCPs, int(count))
+	gcps := make([]GCP, len(list))
+	for i, gcp := range list {
+		gcps[i] = GCP{
+			ID:    C.GoString(gcp.pszId),
+			Info:  C.GoString(gcp.pszInfo),
+			Pixel: float64(gcp.dfGCPPixel),
+			Line:  float64(gcp.dfGCPLine),
+			X:     float64(gcp.dfGCPX),
+			Y:     float64(gcp.dfGCPY),
+			Z:     float64(gcp.dfGCPZ),
+		}
+	}
+	return gcps
+}
+
+// Copy GCPs to a C array, returning nil for an empty list.  The returned
+// function frees the array.
+func cGCPList(gcps []GCP) (*C.GDAL_GCP, func()) {
+	if len(gcps) == 0 {
+		return nil, func() {}
+	}
+	cGCPs := (*C.GDAL_GCP)(C.calloc(C.size_t(len(gcps)), C.sizeof_GDAL_GCP))
+	list := unsafe.Slice(cGCPs, len(gcps))
+	for i, gcp := range gcps {
+		list[i] = C.GDAL_GCP{
+			pszId:      C.CString(gcp.ID),
+			pszInfo:    C.CString(gcp.Info),
+			dfGCPPixel: C.double(gcp.Pixel),
+			dfGCPLine:  C.double(gcp.Line),
+			dfGCPX:     C.double(gcp.X),
+			dfGCPY:     C.double(gcp.Y),
+			dfGCPZ:     C.double(gcp.Z),
+		}
+	}
+	return cGCPs, func() {
+		for _, gcp := range list {
+			C.free(unsafe.Pointer(gcp.pszId))
+			C.free(unsafe.Pointer(gcp.pszInfo))
+		}
+		C.free(unsafe.Pointer(cGCPs))
+	}
+}
 
 /* ==================================================================== */
 /*      major objects (dataset, and, driver, drivermanager).            */
@@ -543,21 +696,25 @@ func (o MajorObject) SetDescription(desc string) {
 	setDescription(unsafe.Pointer(o.cval), desc)
 }
 
-// Fetch metadata, typically pass "" for default domain
+// Fetch the metadata domains, including "" for the default domain
+func (o MajorObject) MetadataDomains() ([]string, error) {
+	return metadataDomains(unsafe.Pointer(o.cval))
+}
+
+// Fetch metadata, typically pass "" for default domain.  Use
+// MetadataDocument for the "xml:" and "json:" domains.
 func (o MajorObject) Metadata(domain string) map[string]string {
 	return metadata(unsafe.Pointer(o.cval), domain)
 }
 
-// Set metadata
-func (object MajorObject) SetMetadata(metadata map[string]string, domain string) {
-	panic("not implemented!")
-	return
+// Replace all metadata of a domain
+func (o MajorObject) SetMetadata(metadata map[string]string, domain string) error {
+	return setMetadata(unsafe.Pointer(o.cval), metadata, domain)
 }
 
 // Fetch a single metadata item
-func (object MajorObject) MetadataItem(name, domain string) string {
-	panic("not implemented!")
-	return ""
+func (o MajorObject) MetadataItem(name, domain string) string {
+	return metadataItem(unsafe.Pointer(o.cval), name, domain)
 }
 
 // Set a single metadata item
@@ -565,6 +722,16 @@ func (o MajorObject) SetMetadataItem(name, value, domain string) error {
 	return setMetadataItem(unsafe.Pointer(o.cval), name, value, domain)
 }
 
+// Fetch the document stored in an "xml:" or "json:" domain
+func (o MajorObject) MetadataDocument(domain string) string {
+	return metadataDocument(unsafe.Pointer(o.cval), domain)
+}
+
+// Store a document in an "xml:" or "json:" domain
+func (o MajorObject) SetMetadataDocument(document, domain string) error {
+	return setMetadataDocument(unsafe.Pointer(o.cval), document, domain)
+}
+
 // Fetch object description
 func (r RasterBand) Description() string {
 	return description(unsafe.Pointer(r.cval))
@@ -575,14 +742,40 @@ func (r RasterBand) SetDescription(desc string) {
 	setDescription(unsafe.Pointer(r.cval), desc)
 }
 
+// Fetch the metadata domains, including "" for the default domain
+func (r RasterBand) MetadataDomains() ([]string, error) {
+	return metadataDomains(unsafe.Pointer(r.cval))
+}
+
+// Fetch metadata, typically pass "" for default domain.  Use
+// MetadataDocument for the "xml:" and "json:" domains.
+func (r RasterBand) Metadata(domain string) map[string]string {
+	return metadata(unsafe.Pointer(r.cval), domain)
+}
+
+// Replace all metadata of a domain
+func (r RasterBand) SetMetadata(metadata map[string]string, domain string) error {
+	return setMetadata(unsafe.Pointer(r.cval), metadata, domain)
+}
+
+// Fetch a single metadata item
+func (r RasterBand) MetadataItem(name, domain string) string {
+	return metadataItem(unsafe.Pointer(r.cval), name, domain)
+}
+
 // Set a single metadata item
 func (r RasterBand) SetMetadataItem(name, value, domain string) error {
 	return setMetadataItem(unsafe.Pointer(r.cval), name, value, domain)
 }
 
-// Fetch metadata, typically pass "" for default domain
-func (r RasterBand) Metadata(domain string) map[string]string {
-	return metadata(unsafe.Pointer(r.cval), domain)
+// Fetch the document stored in an "xml:" or "json:" domain
+func (r RasterBand) MetadataDocument(domain string) string {
+	return metadataDocument(unsafe.Pointer(r.cval), domain)
+}
+
+// Store a document in an "xml:" or "json:" domain
+func (r RasterBand) SetMetadataDocument(document, domain string) error {
+	return setMetadataDocument(unsafe.Pointer(r.cval), document, domain)
 }
 
 // Fetch object description
@@ -595,14 +788,86 @@ func (d Dataset) SetDescription(desc string) {
 	setDescription(unsafe.Pointer(d.cval), desc)
 }
 
+// Fetch the metadata domains, including "" for the default domain
+func (d Dataset) MetadataDomains() ([]string, error) {
+	return metadataDomains(unsafe.Pointer(d.cval))
+}
+
+// Fetch metadata, typically pass "" for default domain.  Use
+// MetadataDocument for the "xml:" and "json:" domains.
+func (d Dataset) Metadata(domain string) map[string]string {
+	return metadata(unsafe.Pointer(d.cval), domain)
+}
+
+// Replace all metadata of a domain
+func (d Dataset) SetMetadata(metadata map[string]string, domain string) error {
+	return setMetadata(unsafe.Pointer(d.cval), metadata, domain)
+}
+
+// Fetch a single metadata item
+func (d Dataset) MetadataItem(name, domain string) string {
+	return metadataItem(unsafe.Pointer(d.cval), name, domain)
+}
+
 // Set a single metadata item
 func (d Dataset) SetMetadataItem(name, value, domain string) error {
 	return setMetadataItem(unsafe.Pointer(d.cval), name, value, domain)
 }
 
-// Fetch metadata, typically pass "" for default domain
-func (d Dataset) Metadata(domain string) map[string]string {
-	return metadata(unsafe.Pointer(d.cval), domain)
+// Fetch the document stored in an "xml:" or "json:" domain
+func (d Dataset) MetadataDocument(domain string) string {
+	return metadataDocument(unsafe.Pointer(d.cval), domain)
+}
+
+// Store a document in an "xml:" or "json:" domain
+func (d Dataset) SetMetadataDocument(document, domain string) error {
+	return setMetadataDocument(unsafe.Pointer(d.cval), document, domain)
+}
+
+// Fetch object description
+func (driver Driver) Description() string {
+	return description(unsafe.Pointer(driver.cval))
+}
+
+// Set object description
+func (driver Driver) SetDescription(desc string) {
+	setDescription(unsafe.Pointer(driver.cval), desc)
+}
+
+// Fetch the metadata domains, including "" for the default domain
+func (driver Driver) MetadataDomains() ([]string, error) {
+	return metadataDomains(unsafe.Pointer(driver.cval))
+}
+
+// Fetch metadata, typically pass "" for default domain.  Use
+// MetadataDocument for the "xml:" and "json:" domains.
+func (driver Driver) Metadata(domain string) map[string]string {
+	return metadata(unsafe.Pointer(driver.cval), domain)
+}
+
+// Replace all metadata of a domain
+func (driver Driver) SetMetadata(metadata map[string]string, domain string) error {
+	return setMetadata(unsafe.Pointer(driver.cval), metadata, domain)
+}
+
+// Fetch a single metadata item
+func (driver Driver) MetadataItem(name, domain string) string {
+	return metadataItem(unsafe.Pointer(driver.cval), name, domain)
+}
+
+// Set a single metadata item
+func (driver Driver) SetMetadataItem(name, value, domain string) error {
+	return setMetadataItem(unsafe.Pointer(driver.cval), name, value, domain)
+}
+
+// Fetch the document stored in an "xml:" or "json:" domain
+func (driver Driver) MetadataDocument(domain string) string {
+	return metadataDocument(unsafe.Pointer(driver.cval), domain)
+}
+
+// Store a document in an "xml:" or "json:" domain
+func (driver Driver) SetMetadataDocument(document, domain string) error {
+	return setMetadataDocument(unsafe.Pointer(driver.cval), document, domain)
 }
 
 /* ==================================================================== */
@@ -611,44 +876,50 @@ func (d Dataset) Metadata(domain string) map[string]string {
 
 // Get the driver to which this dataset relates
 func (dataset Dataset) Driver() Driver {
+	defer runtime.KeepAlive(dataset)
 	driver := Driver{C.GDALGetDatasetDriver(dataset.cval)}
 	return driver
 }
 
 // Unimplemented: GDALGetFileList
 
-// Close the dataset
+// Close the dataset.  Closing a dataset more than once, or one that was
+// not opened by the caller, does nothing.
 func (dataset Dataset) Close() {
-	C.GDALClose(dataset.cval)
-	return
+	dataset.owner.release()
 }
 
 // Fetch X size of raster
 func (dataset Dataset) RasterXSize() int {
+	defer runtime.KeepAlive(dataset)
 	xSize := int(C.GDALGetRasterXSize(dataset.cval))
 	return xSize
 }
 
 // Fetch Y size of raster
 func (dataset Dataset) RasterYSize() int {
+	defer runtime.KeepAlive(dataset)
 	ySize := int(C.GDALGetRasterYSize(dataset.cval))
 	return ySize
 }
 
 // Fetch the number of raster bands in the dataset
 func (dataset Dataset) RasterCount() int {
+	defer runtime.KeepAlive(dataset)
 	count := int(C.GDALGetRasterCount(dataset.cval))
 	return count
 }
 
 // Fetch a raster band object from a dataset
 func (dataset Dataset) RasterBand(band int) RasterBand {
-	rasterBand := RasterBand{C.GDALGetRasterBand(dataset.cval, C.int(band))}
+	defer runtime.KeepAlive(dataset)
+	rasterBand := RasterBand{C.GDALGetRasterBand(dataset.cval, C.int(band)), dataset.owner}
 	return rasterBand
 }
 
 // Add a band to a dataset
 func (dataset Dataset) AddBand(dataType DataType, options []string) error {
+	defer runtime.KeepAlive(dataset)
 	length := len(options)
 	cOptions := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -657,12 +928,13 @@ func (dataset Dataset) AddBand(dataType DataType, options []string) error {
 	}
 	cOptions[length] = (*C.char)(unsafe.Pointer(nil))
 
+	defer captureErrors()()
 	err := C.GDALAddBand(
 		dataset.cval,
 		C.GDALDataType(dataType),
 		(**C.char)(unsafe.Pointer(&cOptions[0])))
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -676,9 +948,12 @@ const (
 	GRA_Cubic            = ResampleAlg(2)
 	GRA_CubicSpline      = ResampleAlg(3)
 	GRA_Lanczos          = ResampleAlg(4)
+	GRA_Average          = ResampleAlg(5)
+	GRA_Mode             = ResampleAlg(6)
 )
 
 func (dataset Dataset) AutoCreateWarpedVRT(srcWKT, dstWKT string, resampleAlg ResampleAlg) (Dataset, error) {
+	defer runtime.KeepAlive(dataset)
 	c_srcWKT := C.CString(srcWKT)
 	defer C.free(unsafe.Pointer(c_srcWKT))
 	c_dstWKT := C.CString(dstWKT)
@@ -686,10 +961,11 @@ func (dataset Dataset) AutoCreateWarpedVRT(srcWKT, dstWKT string, resampleAlg Re
 	/*
 
 	*/
+	defer captureErrors()()
 	h := C.GDALAutoCreateWarpedVRT(dataset.cval, c_srcWKT, c_dstWKT, C.GDALResampleAlg(resampleAlg), 0.0, nil)
-	d := Dataset{h}
+	d := ownDataset(h)
 	if h == nil {
-		return d, fmt.Errorf("AutoCreateWarpedVRT failed")
+		return d, lastError(CPLE_AppDefined, "AutoCreateWarpedVRT failed")
 	}
 	return d, nil
 
@@ -708,41 +984,26 @@ func (dataset Dataset) IO(
 	bandMap []int,
 	pixelSpace, lineSpace, bandSpace int,
 ) error {
-	var dataType DataType
-	var dataPtr unsafe.Pointer
-	switch data := buffer.(type) {
-	case []uint8:
-		dataType = Byte
-		dataPtr = unsafe.Pointer(&data[0])
-	case []int16:
-		dataType = Int16
-		dataPtr = unsafe.Pointer(&data[0])
-	case []uint16:
-		dataType = UInt16
-		dataPtr = unsafe.Pointer(&data[0])
-	case []int32:
-		dataType = Int32
-		dataPtr = unsafe.Pointer(&data[0])
-	case []uint32:
-		dataType = UInt32
-		dataPtr = unsafe.Pointer(&data[0])
-	case []float32:
-		dataType = Float32
-		dataPtr = unsafe.Pointer(&data[0])
-	case []float64:
-		dataType = Float64
-		dataPtr = unsafe.Pointer(&data[0])
-	case []complex64:
-		dataType = CFloat32
-		dataPtr = unsafe.Pointer(&data[0])
-	case []complex128:
-		dataType = CFloat64
-		dataPtr = unsafe.Pointer(&data[0])
-	default:
-		return fmt.Errorf("Error: buffer is not a valid data type (must be a valid numeric slice)")
-	}
-
-	err := C.GDALDatasetRasterIO(
+	defer runtime.KeepAlive(dataset)
+	dataType, dataPtr, length, err := pixelBuffer(buffer)
+	if err != nil {
+		return err
+	}
+	if len(bandMap) != 0 && len(bandMap) < bandCount {
+		return newError(CPLE_IllegalArg, "Error: band map lists %d of %d bands", len(bandMap), bandCount)
+	}
+	err = checkIO(
+		xOff, yOff, xSize, ySize,
+		dataset.RasterXSize(), dataset.RasterYSize(),
+		dataType, length, bufXSize, bufYSize,
+		bandCount, pixelSpace, lineSpace, bandSpace,
+	)
+	if err != nil {
+		return err
+	}
+
+	defer captureErrors()()
+	cErr := C.GDALDatasetRasterIO(
 		dataset.cval,
 		C.GDALRWFlag(rwFlag),
 		C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize),
@@ -750,10 +1011,10 @@ func (dataset Dataset) IO(
 		C.int(bufXSize), C.int(bufYSize),
 		C.GDALDataType(dataType),
 		C.int(bandCount),
-		(*C.int)(unsafe.Pointer(&bandMap[0])),
+		cBandMap(bandMap),
 		C.int(pixelSpace), C.int(lineSpace), C.int(bandSpace))
-	if err != 0 {
-		return error(err)
+	if cErr != 0 {
+		return cplError(cErr)
 	}
 
 	return nil
@@ -768,6 +1029,7 @@ func (dataset Dataset) AdviseRead(
 	bandMap []int,
 	options []string,
 ) error {
+	defer runtime.KeepAlive(dataset)
 	length := len(options)
 	cOptions := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -776,16 +1038,17 @@ func (dataset Dataset) AdviseRead(
 	}
 	cOptions[length] = (*C.char)(unsafe.Pointer(nil))
 
+	defer captureErrors()()
 	err := C.GDALDatasetAdviseRead(
 		dataset.cval,
 		C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize),
 		C.int(bufXSize), C.int(bufYSize),
 		C.GDALDataType(dataType),
 		C.int(bandCount),
-		(*C.int)(unsafe.Pointer(&bandMap[0])),
+		cBandMap(bandMap),
 		(**C.char)(unsafe.Pointer(&cOptions[0])))
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -793,35 +1056,42 @@ func (dataset Dataset) AdviseRead(
 
 // Fetch the projection definition string for this dataset
 func (dataset Dataset) Projection() string {
+	defer runtime.KeepAlive(dataset)
 	proj := C.GoString(C.GDALGetProjectionRef(dataset.cval))
 	return proj
 }
 
 // Set the projection reference string
 func (dataset Dataset) SetProjection(proj string) error {
+	defer runtime.KeepAlive(dataset)
 	cProj := C.CString(proj)
 	defer C.free(unsafe.Pointer(cProj))
 
+	defer captureErrors()()
 	err := C.GDALSetProjection(dataset.cval, cProj)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
 }
 
-// Get the affine transformation coefficients
-func (dataset Dataset) GeoTransform() [6]float64 {
-	var transform [6]float64
-	C.GDALGetGeoTransform(dataset.cval, (*C.double)(unsafe.Pointer(&transform[0])))
-	return transform
+// Get the affine transformation coefficients.  The second result is false,
+// and the transformation is DefaultGeoTransform, if the dataset has none.
+func (dataset Dataset) GeoTransform() (GeoTransform, bool) {
+	defer runtime.KeepAlive(dataset)
+	var transform GeoTransform
+	err := C.GDALGetGeoTransform(dataset.cval, (*C.double)(unsafe.Pointer(&transform[0])))
+	return transform, err == C.CE_None
 }
 
 // Set the affine transformation coefficients
-func (dataset Dataset) SetGeoTransform(transform [6]float64) error {
+func (dataset Dataset) SetGeoTransform(transform GeoTransform) error {
+	defer runtime.KeepAlive(dataset)
+	defer captureErrors()()
 	err := C.GDALSetGeoTransform(dataset.cval, (*C.double)(unsafe.Pointer(&transform[0])))
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -829,16 +1099,45 @@ func (dataset Dataset) SetGeoTransform(transform [6]float64) error {
 
 // Get number of GCPs
 func (dataset Dataset) GDALGetGCPCount() int {
+	defer runtime.KeepAlive(dataset)
 	count := C.GDALGetGCPCount(dataset.cval)
 	return int(count)
 }
 
-// Unimplemented: GDALGetGCPProjection
-// Unimplemented: GDALGetGCPs
-// Unimplemented: GDALSetGCPs
+// Fetch the projection definition string of the GCPs
+func (dataset Dataset) GCPProjection() string {
+	defer runtime.KeepAlive(dataset)
+	proj := C.GoString(C.GDALGetGCPProjection(dataset.cval))
+	return proj
+}
+
+// Fetch the GCPs of the dataset
+func (dataset Dataset) GCPs() []GCP {
+	defer runtime.KeepAlive(dataset)
+	count := C.GDALGetGCPCount(dataset.cval)
+	return goGCPList(count, C.GDALGetGCPs(dataset.cval))
+}
+
+// Replace the GCPs of the dataset and set the projection they are in
+func (dataset Dataset) SetGCPs(gcps []GCP, projection string) error {
+	defer runtime.KeepAlive(dataset)
+	cGCPs, free := cGCPList(gcps)
+	defer free()
+	cProj := C.CString(projection)
+	defer C.free(unsafe.Pointer(cProj))
+
+	defer captureErrors()()
+	err := C.GDALSetGCPs(dataset.cval, C.int(len(gcps)), cGCPs, cProj)
+	if err != 0 {
+		return cplError(err)
+	}
+
+	return nil
+}
 
 // Fetch a format specific internally meaningful handle
 func (dataset Dataset) GDALGetInternalHandle(request string) unsafe.Pointer {
+	defer runtime.KeepAlive(dataset)
 	cRequest := C.CString(request)
 	defer C.free(unsafe.Pointer(cRequest))
 
@@ -848,12 +1147,14 @@ func (dataset Dataset) GDALGetInternalHandle(request string) unsafe.Pointer {
 
 // Add one to dataset reference count
 func (dataset Dataset) GDALReferenceDataset() int {
+	defer runtime.KeepAlive(dataset)
 	count := C.GDALReferenceDataset(dataset.cval)
 	return int(count)
 }
 
 // Subtract one from dataset reference count
 func (dataset Dataset) GDALDereferenceDataset() int {
+	defer runtime.KeepAlive(dataset)
 	count := C.GDALDereferenceDataset(dataset.cval)
 	return int(count)
 }
@@ -868,11 +1169,14 @@ func (dataset Dataset) BuildOverviews(
 	progress ProgressFunc,
 	data interface{},
 ) error {
+	defer runtime.KeepAlive(dataset)
 	cResampling := C.CString(resampling)
 	defer C.free(unsafe.Pointer(cResampling))
 
-	arg := &goGDALProgressFuncProxyArgs{progress, data}
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
 
+	defer captureErrors()()
 	err := C.GDALBuildOverviews(
 		dataset.cval,
 		cResampling,
@@ -880,35 +1184,55 @@ func (dataset Dataset) BuildOverviews(
 		(*C.int)(unsafe.Pointer(&overviewList[0])),
 		C.int(nBands),
 		(*C.int)(unsafe.Pointer(&bandList[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		pf,
+		pa,
 	)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
 }
 
+// Build raster overview(s), stopping when ctx is done
+func (dataset Dataset) BuildOverviewsContext(
+	ctx context.Context,
+	resampling string,
+	nOverviews int,
+	overviewList []int,
+	nBands int,
+	bandList []int,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	return runContext(ctx, progress, data, func(progress ProgressFunc) error {
+		return dataset.BuildOverviews(resampling, nOverviews, overviewList, nBands, bandList, progress, nil)
+	})
+}
+
 // Unimplemented: GDALGetOpenDatasets
 
 // Return access flag
 func (dataset Dataset) Access() Access {
+	defer runtime.KeepAlive(dataset)
 	accessVal := C.GDALGetAccess(dataset.cval)
 	return Access(accessVal)
 }
 
 // Write all write cached data to disk
 func (dataset Dataset) FlushCache() {
+	defer runtime.KeepAlive(dataset)
 	C.GDALFlushCache(dataset.cval)
 	return
 }
 
 // Adds a mask band to the dataset
 func (dataset Dataset) CreateMaskBand(flags int) error {
+	defer runtime.KeepAlive(dataset)
+	defer captureErrors()()
 	err := C.GDALCreateDatasetMaskBand(dataset.cval, C.int(flags))
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -921,7 +1245,10 @@ func (sourceDataset Dataset) CopyWholeRaster(
 	progress ProgressFunc,
 	data interface{},
 ) error {
-	arg := &goGDALProgressFuncProxyArgs{progress, data}
+	defer runtime.KeepAlive(sourceDataset)
+	defer runtime.KeepAlive(destDataset)
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
 
 	length := len(options)
 	cOptions := make([]*C.char, length+1)
@@ -931,32 +1258,48 @@ func (sourceDataset Dataset) CopyWholeRaster(
 	}
 	cOptions[length] = (*C.char)(unsafe.Pointer(nil))
 
+	defer captureErrors()()
 	err := C.GDALDatasetCopyWholeRaster(
 		sourceDataset.cval,
 		destDataset.cval,
 		(**C.char)(unsafe.Pointer(&cOptions[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		pf,
+		pa,
 	)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
 }
 
+// Copy all dataset raster data, stopping when ctx is done
+func (sourceDataset Dataset) CopyWholeRasterContext(
+	ctx context.Context,
+	destDataset Dataset,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	return runContext(ctx, progress, data, func(progress ProgressFunc) error {
+		return sourceDataset.CopyWholeRaster(destDataset, options, progress, nil)
+	})
+}
+
 /* ==================================================================== */
 /*      GDALRasterBand ... one band/channel in a dataset.               */
 /* ==================================================================== */
 
 // Fetch the pixel data type for this band
 func (rasterBand RasterBand) RasterDataType() DataType {
+	defer runtime.KeepAlive(rasterBand)
 	dataType := C.GDALGetRasterDataType(rasterBand.cval)
 	return DataType(dataType)
 }
 
 // Fetch the "natural" block size of this band
 func (rasterBand RasterBand) BlockSize() (int, int) {
+	defer runtime.KeepAlive(rasterBand)
 	var xSize, ySize int
 	C.GDALGetBlockSize(rasterBand.cval, (*C.int)(unsafe.Pointer(&xSize)), (*C.int)(unsafe.Pointer(&ySize)))
 	return xSize, ySize
@@ -968,6 +1311,7 @@ func (rasterBand RasterBand) AdviseRead(
 	dataType DataType,
 	options []string,
 ) error {
+	defer runtime.KeepAlive(rasterBand)
 	length := len(options)
 	cOptions := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -976,6 +1320,7 @@ func (rasterBand RasterBand) AdviseRead(
 	}
 	cOptions[length] = (*C.char)(unsafe.Pointer(nil))
 
+	defer captureErrors()()
 	err := C.GDALRasterAdviseRead(
 		rasterBand.cval,
 		C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize), C.int(bufXSize), C.int(bufYSize),
@@ -983,7 +1328,7 @@ func (rasterBand RasterBand) AdviseRead(
 		(**C.char)(unsafe.Pointer(&cOptions[0])),
 	)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -997,41 +1342,23 @@ func (rasterBand RasterBand) IO(
 	bufXSize, bufYSize int,
 	pixelSpace, lineSpace int,
 ) error {
-	var dataType DataType
-	var dataPtr unsafe.Pointer
-	switch data := buffer.(type) {
-	case []uint8:
-		dataType = Byte
-		dataPtr = unsafe.Pointer(&data[0])
-	case []int16:
-		dataType = Int16
-		dataPtr = unsafe.Pointer(&data[0])
-	case []uint16:
-		dataType = UInt16
-		dataPtr = unsafe.Pointer(&data[0])
-	case []int32:
-		dataType = Int32
-		dataPtr = unsafe.Pointer(&data[0])
-	case []uint32:
-		dataType = UInt32
-		dataPtr = unsafe.Pointer(&data[0])
-	case []float32:
-		dataType = Float32
-		dataPtr = unsafe.Pointer(&data[0])
-	case []float64:
-		dataType = Float64
-		dataPtr = unsafe.Pointer(&data[0])
-	case []complex64:
-		dataType = CFloat32
-		dataPtr = unsafe.Pointer(&data[0])
-	case []complex128:
-		dataType = CFloat64
-		dataPtr = unsafe.Pointer(&data[0])
-	default:
-		return fmt.Errorf("Error: buffer is not a valid data type (must be a valid numeric slice)")
-	}
-
-	err := C.GDALRasterIO(
+	defer runtime.KeepAlive(rasterBand)
+	dataType, dataPtr, length, err := pixelBuffer(buffer)
+	if err != nil {
+		return err
+	}
+	err = checkIO(
+		xOff, yOff, xSize, ySize,
+		rasterBand.XSize(), rasterBand.YSize(),
+		dataType, length, bufXSize, bufYSize,
+		1, pixelSpace, lineSpace, 0,
+	)
+	if err != nil {
+		return err
+	}
+
+	defer captureErrors()()
+	cErr := C.GDALRasterIO(
 		rasterBand.cval,
 		C.GDALRWFlag(rwFlag),
 		C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize),
@@ -1039,8 +1366,8 @@ func (rasterBand RasterBand) IO(
 		C.int(bufXSize), C.int(bufYSize),
 		C.GDALDataType(dataType),
 		C.int(pixelSpace), C.int(lineSpace))
-	if err != 0 {
-		return error(err)
+	if cErr != 0 {
+		return cplError(cErr)
 	}
 
 	return nil
@@ -1048,9 +1375,11 @@ func (rasterBand RasterBand) IO(
 
 // Read a block of image data efficiently
 func (rasterBand RasterBand) ReadBlock(xOff, yOff int, dataPtr unsafe.Pointer) error {
+	defer runtime.KeepAlive(rasterBand)
+	defer captureErrors()()
 	err := C.GDALReadBlock(rasterBand.cval, C.int(xOff), C.int(yOff), dataPtr)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1058,9 +1387,11 @@ func (rasterBand RasterBand) ReadBlock(xOff, yOff int, dataPtr unsafe.Pointer) e
 
 // Write a block of image data efficiently
 func (rasterBand RasterBand) WriteBlock(xOff, yOff int, dataPtr unsafe.Pointer) error {
+	defer runtime.KeepAlive(rasterBand)
+	defer captureErrors()()
 	err := C.GDALWriteBlock(rasterBand.cval, C.int(xOff), C.int(yOff), dataPtr)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1068,45 +1399,53 @@ func (rasterBand RasterBand) WriteBlock(xOff, yOff int, dataPtr unsafe.Pointer)
 
 // Fetch X size of raster
 func (rasterBand RasterBand) XSize() int {
+	defer runtime.KeepAlive(rasterBand)
 	xSize := C.GDALGetRasterBandXSize(rasterBand.cval)
 	return int(xSize)
 }
 
 // Fetch Y size of raster
 func (rasterBand RasterBand) YSize() int {
+	defer runtime.KeepAlive(rasterBand)
 	ySize := C.GDALGetRasterBandYSize(rasterBand.cval)
 	return int(ySize)
 }
 
 // Find out if we have update permission for this band
 func (rasterBand RasterBand) GetAccess() Access {
+	defer runtime.KeepAlive(rasterBand)
 	access := C.GDALGetRasterAccess(rasterBand.cval)
 	return Access(access)
 }
 
 // Fetch the band number of this raster band
 func (rasterBand RasterBand) BandNumber() int {
+	defer runtime.KeepAlive(rasterBand)
 	bandNumber := C.GDALGetBandNumber(rasterBand.cval)
 	return int(bandNumber)
 }
 
 // Fetch the owning dataset handle
 func (rasterBand RasterBand) GetDataset() Dataset {
+	defer runtime.KeepAlive(rasterBand)
 	dataset := C.GDALGetBandDataset(rasterBand.cval)
-	return Dataset{dataset}
+	return Dataset{dataset, borrow(rasterBand.parent)}
 }
 
 // How should this band be interpreted as color?
 func (rasterBand RasterBand) ColorInterp() ColorInterp {
+	defer runtime.KeepAlive(rasterBand)
 	colorInterp := C.GDALGetRasterColorInterpretation(rasterBand.cval)
 	return ColorInterp(colorInterp)
 }
 
 // Set color interpretation of the raster band
 func (rasterBand RasterBand) SetColorInterp(colorInterp ColorInterp) error {
+	defer runtime.KeepAlive(rasterBand)
+	defer captureErrors()()
 	err := C.GDALSetRasterColorInterpretation(rasterBand.cval, C.GDALColorInterp(colorInterp))
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1114,15 +1453,19 @@ func (rasterBand RasterBand) SetColorInterp(colorInterp ColorInterp) error {
 
 // Fetch the color table associated with this raster band
 func (rasterBand RasterBand) ColorTable() ColorTable {
+	defer runtime.KeepAlive(rasterBand)
 	colorTable := C.GDALGetRasterColorTable(rasterBand.cval)
-	return ColorTable{colorTable}
+	return ColorTable{colorTable, borrow(rasterBand.parent)}
 }
 
 // Set the raster color table for this raster band
 func (rasterBand RasterBand) SetColorTable(colorTable ColorTable) error {
+	defer runtime.KeepAlive(rasterBand)
+	defer runtime.KeepAlive(colorTable)
+	defer captureErrors()()
 	err := C.GDALSetRasterColorTable(rasterBand.cval, colorTable.cval)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1130,24 +1473,28 @@ func (rasterBand RasterBand) SetColorTable(colorTable ColorTable) error {
 
 // Check for arbitrary overviews
 func (rasterBand RasterBand) HasArbitraryOverviews() int {
+	defer runtime.KeepAlive(rasterBand)
 	yes := C.GDALHasArbitraryOverviews(rasterBand.cval)
 	return int(yes)
 }
 
 // Return the number of overview layers available
 func (rasterBand RasterBand) OverviewCount() int {
+	defer runtime.KeepAlive(rasterBand)
 	count := C.GDALGetOverviewCount(rasterBand.cval)
 	return int(count)
 }
 
 // Fetch overview raster band object
 func (rasterBand RasterBand) Overview(level int) RasterBand {
+	defer runtime.KeepAlive(rasterBand)
 	overview := C.GDALGetOverview(rasterBand.cval, C.int(level))
-	return RasterBand{overview}
+	return RasterBand{overview, rasterBand.parent}
 }
 
 // Fetch the no data value for this band
 func (rasterBand RasterBand) NoDataValue() (val float64, valid bool) {
+	defer runtime.KeepAlive(rasterBand)
 	var success int
 	noDataVal := C.GDALGetRasterNoDataValue(rasterBand.cval, (*C.int)(unsafe.Pointer(&success)))
 	return float64(noDataVal), (success != 0)
@@ -1155,9 +1502,11 @@ func (rasterBand RasterBand) NoDataValue() (val float64, valid bool) {
 
 // Set the no data value for this band
 func (rasterBand RasterBand) SetNoDataValue(val float64) error {
+	defer runtime.KeepAlive(rasterBand)
+	defer captureErrors()()
 	err := C.GDALSetRasterNoDataValue(rasterBand.cval, C.double(val))
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1165,27 +1514,20 @@ func (rasterBand RasterBand) SetNoDataValue(val float64) error {
 
 // Fetch the list of category names for this raster
 func (rasterBand RasterBand) CategoryNames() []string {
+	defer runtime.KeepAlive(rasterBand)
 	p := C.GDALGetRasterCategoryNames(rasterBand.cval)
 	if p == nil {
 		return nil
 	}
 
-	var strings []string
-	q := uintptr(unsafe.Pointer(p))
-	for {
-		p = (**C.char)(unsafe.Pointer(q))
-		if *p == nil {
-			break
-		}
-		strings = append(strings, C.GoString(*p))
-		q += unsafe.Sizeof(q)
-	}
+	strings := goStringList(p)
 
 	return strings
 }
 
 // Set the category names for this band
 func (rasterBand RasterBand) SetRasterCategoryNames(names []string) error {
+	defer runtime.KeepAlive(rasterBand)
 	length := len(names)
 	cStrings := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -1194,10 +1536,11 @@ func (rasterBand RasterBand) SetRasterCategoryNames(names []string) error {
 	}
 	cStrings[length] = (*C.char)(unsafe.Pointer(nil))
 
+	defer captureErrors()()
 	err := C.GDALSetRasterCategoryNames(rasterBand.cval, (**C.char)(unsafe.Pointer(&cStrings[0])))
 
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1205,6 +1548,7 @@ func (rasterBand RasterBand) SetRasterCategoryNames(names []string) error {
 
 // Fetch the minimum value for this band
 func (rasterBand RasterBand) GetMinimum() (val float64, valid bool) {
+	defer runtime.KeepAlive(rasterBand)
 	var success int
 	min := C.GDALGetRasterMinimum(rasterBand.cval, (*C.int)(unsafe.Pointer(&success)))
 	return float64(min), (success != 0)
@@ -1212,6 +1556,7 @@ func (rasterBand RasterBand) GetMinimum() (val float64, valid bool) {
 
 // Fetch the maximum value for this band
 func (rasterBand RasterBand) GetMaximum() (val float64, valid bool) {
+	defer runtime.KeepAlive(rasterBand)
 	var success int
 	max := C.GDALGetRasterMaximum(rasterBand.cval, (*C.int)(unsafe.Pointer(&success)))
 	return float64(max), (success != 0)
@@ -1219,6 +1564,7 @@ func (rasterBand RasterBand) GetMaximum() (val float64, valid bool) {
 
 // Fetch image statistics
 func (rasterBand RasterBand) GetStatistics(approxOK, force int) (min, max, mean, stdDev float64) {
+	defer runtime.KeepAlive(rasterBand)
 	C.GDALGetRasterStatistics(
 		rasterBand.cval,
 		C.int(approxOK),
@@ -1237,7 +1583,9 @@ func (rasterBand RasterBand) ComputeStatistics(
 	progress ProgressFunc,
 	data interface{},
 ) (min, max, mean, stdDev float64) {
-	arg := &goGDALProgressFuncProxyArgs{progress, data}
+	defer runtime.KeepAlive(rasterBand)
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
 
 	C.GDALComputeRasterStatistics(
 		rasterBand.cval,
@@ -1246,14 +1594,16 @@ func (rasterBand RasterBand) ComputeStatistics(
 		(*C.double)(unsafe.Pointer(&max)),
 		(*C.double)(unsafe.Pointer(&mean)),
 		(*C.double)(unsafe.Pointer(&stdDev)),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		pf,
+		pa,
 	)
 	return min, max, mean, stdDev
 }
 
 // Set statistics on raster band
 func (rasterBand RasterBand) SetStatistics(min, max, mean, stdDev float64) error {
+	defer runtime.KeepAlive(rasterBand)
+	defer captureErrors()()
 	err := C.GDALSetRasterStatistics(
 		rasterBand.cval,
 		C.double(min),
@@ -1261,7 +1611,7 @@ func (rasterBand RasterBand) SetStatistics(min, max, mean, stdDev float64) error
 		C.double(mean),
 		C.double(stdDev))
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1269,18 +1619,21 @@ func (rasterBand RasterBand) SetStatistics(min, max, mean, stdDev float64) error
 
 // Return raster unit type
 func (rasterBand RasterBand) GetUnitType() string {
+	defer runtime.KeepAlive(rasterBand)
 	cString := C.GDALGetRasterUnitType(rasterBand.cval)
 	return C.GoString(cString)
 }
 
 // Set unit type
 func (rasterBand RasterBand) SetUnitType(unit string) error {
+	defer runtime.KeepAlive(rasterBand)
 	cString := C.CString(unit)
 	defer C.free(unsafe.Pointer(cString))
 
+	defer captureErrors()()
 	err := C.GDALSetRasterUnitType(rasterBand.cval, cString)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1288,6 +1641,7 @@ func (rasterBand RasterBand) SetUnitType(unit string) error {
 
 // Fetch the raster value offset
 func (rasterBand RasterBand) GetOffset() (float64, bool) {
+	defer runtime.KeepAlive(rasterBand)
 	var success int
 	val := C.GDALGetRasterOffset(rasterBand.cval, (*C.int)(unsafe.Pointer(&success)))
 	return float64(val), (success != 0)
@@ -1295,9 +1649,11 @@ func (rasterBand RasterBand) GetOffset() (float64, bool) {
 
 // Set scaling offset
 func (rasterBand RasterBand) SetOffset(offset float64) error {
+	defer runtime.KeepAlive(rasterBand)
+	defer captureErrors()()
 	err := C.GDALSetRasterOffset(rasterBand.cval, C.double(offset))
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1305,6 +1661,7 @@ func (rasterBand RasterBand) SetOffset(offset float64) error {
 
 // Fetch the raster value scale
 func (rasterBand RasterBand) GetScale() (float64, bool) {
+	defer runtime.KeepAlive(rasterBand)
 	var success int
 	val := C.GDALGetRasterScale(rasterBand.cval, (*C.int)(unsafe.Pointer(&success)))
 	return float64(val), (success != 0)
@@ -1312,9 +1669,11 @@ func (rasterBand RasterBand) GetScale() (float64, bool) {
 
 // Set scaling ratio
 func (rasterBand RasterBand) SetScale(scale float64) error {
+	defer runtime.KeepAlive(rasterBand)
+	defer captureErrors()()
 	err := C.GDALSetRasterScale(rasterBand.cval, C.double(scale))
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1322,6 +1681,7 @@ func (rasterBand RasterBand) SetScale(scale float64) error {
 
 // Compute the min / max values for a band
 func (rasterBand RasterBand) ComputeMinMax(approxOK int) (min, max float64) {
+	defer runtime.KeepAlive(rasterBand)
 	var minmax [2]float64
 	C.GDALComputeRasterMinMax(
 		rasterBand.cval,
@@ -1332,6 +1692,7 @@ func (rasterBand RasterBand) ComputeMinMax(approxOK int) (min, max float64) {
 
 // Flush raster data cache
 func (rasterBand RasterBand) FlushCache() {
+	defer runtime.KeepAlive(rasterBand)
 	C.GDALFlushRasterCache(rasterBand.cval)
 }
 
@@ -1343,61 +1704,91 @@ func (rb RasterBand) Histogram(
 	progress ProgressFunc,
 	data interface{},
 ) ([]int, error) {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
-	}
+	defer runtime.KeepAlive(rb)
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
 
-	histogram := make([]int, buckets)
+	if buckets <= 0 {
+		return nil, newError(CPLE_IllegalArg, "Error: invalid bucket count %d", buckets)
+	}
+	cHistogram := make([]C.int, buckets)
 
+	defer captureErrors()()
 	err := C.GDALGetRasterHistogram(
 		rb.cval,
 		C.double(min),
 		C.double(max),
 		C.int(buckets),
-		(*C.int)(unsafe.Pointer(&histogram[0])),
+		&cHistogram[0],
 		C.int(includeOutOfRange),
 		C.int(approxOK),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		pf,
+		pa,
 	)
 	if err != 0 {
-		return nil, error(err)
+		return nil, cplError(err)
 	}
 
+	histogram := make([]int, buckets)
+	for i, count := range cHistogram {
+		histogram[i] = int(count)
+	}
 	return histogram, nil
 }
 
+// Compute raster histogram, stopping when ctx is done
+func (rb RasterBand) HistogramContext(
+	ctx context.Context,
+	min, max float64,
+	buckets int,
+	includeOutOfRange, approxOK int,
+	progress ProgressFunc,
+	data interface{},
+) ([]int, error) {
+	var histogram []int
+	err := runContext(ctx, progress, data, func(progress ProgressFunc) error {
+		var err error
+		histogram, err = rb.Histogram(min, max, buckets, includeOutOfRange, approxOK, progress, nil)
+		return err
+	})
+	return histogram, err
+}
+
 // Fetch default raster histogram
 func (rb RasterBand) DefaultHistogram(
 	force int,
 	progress ProgressFunc,
 	data interface{},
 ) (min, max float64, buckets int, histogram []int, err error) {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
-	}
+	defer runtime.KeepAlive(rb)
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
 
+	var cMin, cMax C.double
+	var cBuckets C.int
 	var cHistogram *C.int
 
+	defer captureErrors()()
 	cErr := C.GDALGetDefaultHistogram(
 		rb.cval,
-		(*C.double)(&min),
-		(*C.double)(&max),
-		(*C.int)(unsafe.Pointer(&buckets)),
+		&cMin,
+		&cMax,
+		&cBuckets,
 		&cHistogram,
 		C.int(force),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		pf,
+		pa,
 	)
 	if cErr != 0 {
-		return min, max, buckets, histogram, error(cErr)
+		return min, max, buckets, histogram, cplError(cErr)
 	}
+	defer C.VSIFree(unsafe.Pointer(cHistogram))
 
-	sliceHeader := (*reflect.SliceHeader)(unsafe.Pointer(&histogram))
-	sliceHeader.Cap = buckets
-	sliceHeader.Len = buckets
-	sliceHeader.Data = uintptr(unsafe.Pointer(cHistogram))
-
+	min, max, buckets = float64(cMin), float64(cMax), int(cBuckets)
+	histogram = make([]int, buckets)
+	for i, count := range unsafe.Slice(cHistogram, buckets) {
+		histogram[i] = int(count)
+	}
 	return min, max, buckets, histogram, nil
 }
 
@@ -1411,9 +1802,11 @@ func (rb RasterBand) DefaultHistogram(
 
 // Fill this band with a constant value
 func (rasterBand RasterBand) Fill(real, imaginary float64) error {
+	defer runtime.KeepAlive(rasterBand)
+	defer captureErrors()()
 	err := C.GDALFillRaster(rasterBand.cval, C.double(real), C.double(imaginary))
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1425,15 +1818,19 @@ func (rasterBand RasterBand) Fill(real, imaginary float64) error {
 
 // Fetch default Raster Attribute Table
 func (rasterBand RasterBand) GetDefaultRAT() RasterAttributeTable {
+	defer runtime.KeepAlive(rasterBand)
 	rat := C.GDALGetDefaultRAT(rasterBand.cval)
-	return RasterAttributeTable{rat}
+	return RasterAttributeTable{rat, borrow(rasterBand.parent)}
 }
 
 // Set default Raster Attribute Table
 func (rasterBand RasterBand) SetDefaultRAT(rat RasterAttributeTable) error {
+	defer runtime.KeepAlive(rasterBand)
+	defer runtime.KeepAlive(rat)
+	defer captureErrors()()
 	err := C.GDALSetDefaultRAT(rasterBand.cval, rat.cval)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1443,21 +1840,25 @@ func (rasterBand RasterBand) SetDefaultRAT(rat RasterAttributeTable) error {
 
 // Return the mask band associated with the band
 func (rasterBand RasterBand) GetMaskBand() RasterBand {
+	defer runtime.KeepAlive(rasterBand)
 	mask := C.GDALGetMaskBand(rasterBand.cval)
-	return RasterBand{mask}
+	return RasterBand{mask, rasterBand.parent}
 }
 
 // Return the status flags of the mask band associated with the band
 func (rasterBand RasterBand) GetMaskFlags() int {
+	defer runtime.KeepAlive(rasterBand)
 	flags := C.GDALGetMaskFlags(rasterBand.cval)
 	return int(flags)
 }
 
 // Adds a mask band to the current band
 func (rasterBand RasterBand) CreateMaskBand(flags int) error {
+	defer runtime.KeepAlive(rasterBand)
+	defer captureErrors()()
 	err := C.GDALCreateMaskBand(rasterBand.cval, C.int(flags))
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1470,7 +1871,10 @@ func (sourceRaster RasterBand) RasterBandCopyWholeRaster(
 	progress ProgressFunc,
 	data interface{},
 ) error {
-	arg := &goGDALProgressFuncProxyArgs{progress, data}
+	defer runtime.KeepAlive(sourceRaster)
+	defer runtime.KeepAlive(destRaster)
+	pf, pa, release := progressProxy(progress, data)
+	defer release()
 
 	length := len(options)
 	cOptions := make([]*C.char, length+1)
@@ -1480,15 +1884,16 @@ func (sourceRaster RasterBand) RasterBandCopyWholeRaster(
 	}
 	cOptions[length] = (*C.char)(unsafe.Pointer(nil))
 
+	defer captureErrors()()
 	err := C.GDALRasterBandCopyWholeRaster(
 		sourceRaster.cval,
 		destRaster.cval,
 		(**C.char)(unsafe.Pointer(&cOptions[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		pf,
+		pa,
 	)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1512,34 +1917,38 @@ func (sourceRaster RasterBand) RasterBandCopyWholeRaster(
 // Construct a new color table
 func CreateColorTable(interp PaletteInterp) ColorTable {
 	ct := C.GDALCreateColorTable(C.GDALPaletteInterp(interp))
-	return ColorTable{ct}
+	return ownColorTable(ct)
 }
 
-// Destroy the color table
+// Destroy the color table.  Tables owned by a raster band are left alone.
 func (ct ColorTable) Destroy() {
-	C.GDALDestroyColorTable(ct.cval)
+	ct.owner.release()
 }
 
 // Make a copy of the color table
 func (ct ColorTable) Clone() ColorTable {
+	defer runtime.KeepAlive(ct)
 	newCT := C.GDALCloneColorTable(ct.cval)
-	return ColorTable{newCT}
+	return ownColorTable(newCT)
 }
 
 // Fetch palette interpretation
 func (ct ColorTable) PaletteInterpretation() PaletteInterp {
+	defer runtime.KeepAlive(ct)
 	pi := C.GDALGetPaletteInterpretation(ct.cval)
 	return PaletteInterp(pi)
 }
 
 // Get number of color entries in table
 func (ct ColorTable) EntryCount() int {
+	defer runtime.KeepAlive(ct)
 	count := C.GDALGetColorEntryCount(ct.cval)
 	return int(count)
 }
 
 // Fetch a color entry from table
 func (ct ColorTable) Entry(index int) ColorEntry {
+	defer runtime.KeepAlive(ct)
 	entry := C.GDALGetColorEntry(ct.cval, C.int(index))
 	return ColorEntry{entry}
 }
@@ -1548,11 +1957,13 @@ func (ct ColorTable) Entry(index int) ColorEntry {
 
 // Set entry in color table
 func (ct ColorTable) SetEntry(index int, entry ColorEntry) {
+	defer runtime.KeepAlive(ct)
 	C.GDALSetColorEntry(ct.cval, C.int(index), entry.cval)
 }
 
 // Create color ramp
 func (ct ColorTable) CreateColorRamp(start, end int, startColor, endColor ColorEntry) {
+	defer runtime.KeepAlive(ct)
 	C.GDALCreateColorRamp(ct.cval, C.int(start), startColor.cval, C.int(end), endColor.cval)
 }
 
@@ -1595,70 +2006,80 @@ const (
 // Construct empty raster attribute table
 func CreateRasterAttributeTable() RasterAttributeTable {
 	rat := C.GDALCreateRasterAttributeTable()
-	return RasterAttributeTable{rat}
+	return ownRasterAttributeTable(rat)
 }
 
-// Destroy a RAT
+// Destroy a RAT.  Tables owned by a raster band are left alone.
 func (rat RasterAttributeTable) Destroy() {
-	C.GDALDestroyRasterAttributeTable(rat.cval)
+	rat.owner.release()
 }
 
 // Fetch table column count
 func (rat RasterAttributeTable) ColumnCount() int {
+	defer runtime.KeepAlive(rat)
 	count := C.GDALRATGetColumnCount(rat.cval)
 	return int(count)
 }
 
 // Fetch the name of indicated column
 func (rat RasterAttributeTable) NameOfCol(index int) string {
+	defer runtime.KeepAlive(rat)
 	name := C.GDALRATGetNameOfCol(rat.cval, C.int(index))
 	return C.GoString(name)
 }
 
 // Fetch the usage of indicated column
 func (rat RasterAttributeTable) UsageOfCol(index int) RATFieldUsage {
+	defer runtime.KeepAlive(rat)
 	rfu := C.GDALRATGetUsageOfCol(rat.cval, C.int(index))
 	return RATFieldUsage(rfu)
 }
 
 // Fetch the type of indicated column
 func (rat RasterAttributeTable) TypeOfCol(index int) RATFieldType {
+	defer runtime.KeepAlive(rat)
 	rft := C.GDALRATGetTypeOfCol(rat.cval, C.int(index))
 	return RATFieldType(rft)
 }
 
 // Fetch column index for indicated usage
 func (rat RasterAttributeTable) ColOfUsage(rfu RATFieldUsage) int {
+	defer runtime.KeepAlive(rat)
 	index := C.GDALRATGetColOfUsage(rat.cval, C.GDALRATFieldUsage(rfu))
 	return int(index)
 }
 
 // Fetch row count
 func (rat RasterAttributeTable) RowCount() int {
+	defer runtime.KeepAlive(rat)
 	count := C.GDALRATGetRowCount(rat.cval)
 	return int(count)
 }
 
 // Fetch field value as string
 func (rat RasterAttributeTable) ValueAsString(row, field int) string {
+	defer runtime.KeepAlive(rat)
 	cString := C.GDALRATGetValueAsString(rat.cval, C.int(row), C.int(field))
 	return C.GoString(cString)
 }
 
 // Fetch field value as integer
 func (rat RasterAttributeTable) ValueAsInt(row, field int) int {
+	defer runtime.KeepAlive(rat)
 	val := C.GDALRATGetValueAsInt(rat.cval, C.int(row), C.int(field))
 	return int(val)
 }
 
 // Fetch field value as float64
 func (rat RasterAttributeTable) ValueAsFloat64(row, field int) float64 {
+	defer runtime.KeepAlive(rat)
 	val := C.GDALRATGetValueAsDouble(rat.cval, C.int(row), C.int(field))
 	return float64(val)
 }
 
 // Set field value from string
 func (rat RasterAttributeTable) SetValueAsString(row, field int, val string) {
+	defer runtime.KeepAlive(rat)
 	cVal := C.CString(val)
 	defer C.free(unsafe.Pointer(cVal))
 	C.GDALRATSetValueAsString(rat.cval, C.int(row), C.int(field), cVal)
@@ -1666,26 +2087,31 @@ func (rat RasterAttributeTable) SetValueAsString(row, field int, val string) {
 
 // Set field value from integer
 func (rat RasterAttributeTable) SetValueAsInt(row, field, val int) {
+	defer runtime.KeepAlive(rat)
 	C.GDALRATSetValueAsInt(rat.cval, C.int(row), C.int(field), C.int(val))
 }
 
 // Set field value from float64
 func (rat RasterAttributeTable) SetValueAsFloat64(row, field int, val float64) {
+	defer runtime.KeepAlive(rat)
 	C.GDALRATSetValueAsDouble(rat.cval, C.int(row), C.int(field), C.double(val))
 }
 
 // Set row count
 func (rat RasterAttributeTable) SetRowCount(count int) {
+	defer runtime.KeepAlive(rat)
 	C.GDALRATSetRowCount(rat.cval, C.int(count))
 }
 
 // Create new column
 func (rat RasterAttributeTable) CreateColumn(name string, rft RATFieldType, rfu RATFieldUsage) error {
+	defer runtime.KeepAlive(rat)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	err := C.GDALRATCreateColumn(rat.cval, cName, C.GDALRATFieldType(rft), C.GDALRATFieldUsage(rfu))
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1693,9 +2119,11 @@ func (rat RasterAttributeTable) CreateColumn(name string, rft RATFieldType, rfu
 
 // Set linear binning information
 func (rat RasterAttributeTable) SetLinearBinning(row0min, binsize float64) error {
+	defer runtime.KeepAlive(rat)
+	defer captureErrors()()
 	err := C.GDALRATSetLinearBinning(rat.cval, C.double(row0min), C.double(binsize))
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1703,15 +2131,19 @@ func (rat RasterAttributeTable) SetLinearBinning(row0min, binsize float64) error
 
 // Fetch linear binning information
 func (rat RasterAttributeTable) LinearBinning() (row0min, binsize float64, exists bool) {
+	defer runtime.KeepAlive(rat)
 	success := C.GDALRATGetLinearBinning(rat.cval, (*C.double)(&row0min), (*C.double)(&binsize))
 	return row0min, binsize, success != 0
 }
 
 // Initialize RAT from color table
 func (rat RasterAttributeTable) FromColorTable(ct ColorTable) error {
+	defer runtime.KeepAlive(rat)
+	defer runtime.KeepAlive(ct)
+	defer captureErrors()()
 	err := C.GDALRATInitializeFromColorTable(rat.cval, ct.cval)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
@@ -1719,8 +2151,9 @@ func (rat RasterAttributeTable) FromColorTable(ct ColorTable) error {
 
 // Translate RAT to a color table
 func (rat RasterAttributeTable) ToColorTable(count int) ColorTable {
+	defer runtime.KeepAlive(rat)
 	ct := C.GDALRATTranslateToColorTable(rat.cval, C.int(count))
-	return ColorTable{ct}
+	return ownColorTable(ct)
 }
 
 // Dump RAT in readable form to a file
@@ -1728,6 +2161,7 @@ func (rat RasterAttributeTable) ToColorTable(count int) ColorTable {
 
 // Get row for pixel value
 func (rat RasterAttributeTable) RowOfValue(val float64) (int, bool) {
+	defer runtime.KeepAlive(rat)
 	row := C.GDALRATGetRowOfValue(rat.cval, C.double(val))
 	return int(row), row != -1
 }
@@ -1787,6 +2221,33 @@ func FlushCacheBlock() bool {
 /*      Generic metadata functions.                                     */
 /* -------------------------------------------------------------------- */
 
+// Report whether the domain holds a single document instead of name=value
+// pairs
+func isDocumentDomain(domain string) bool {
+	return strings.HasPrefix(domain, "xml:") || strings.HasPrefix(domain, "json:")
+}
+
+func metadataDomains(object unsafe.Pointer) ([]string, error) {
+	defer captureErrors()()
+	list := C.goGDALGetMetadataDomainList((C.GDALMajorObjectH)(object))
+	if list == nil {
+		return nil, pendingError()
+	}
+	defer C.CSLDestroy(list)
+	return goStringList(list), nil
+}
+
+func metadataItem(object unsafe.Pointer, name, domain string) string {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	c_domain := C.CString(domain)
+	defer C.free(unsafe.Pointer(c_domain))
+
+	value := C.GDALGetMetadataItem((C.GDALMajorObjectH)(object), c_name, c_domain)
+	return C.GoString(value)
+}
+
 func setMetadataItem(object unsafe.Pointer, name, value, domain string) error {
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
@@ -1797,15 +2258,61 @@ func setMetadataItem(object unsafe.Pointer, name, value, domain string) error {
 	c_domain := C.CString(domain)
 	defer C.free(unsafe.Pointer(c_domain))
 
+	defer captureErrors()()
 	err := C.GDALSetMetadataItem((C.GDALMajorObjectH)(object), c_name, c_value, c_domain)
 	if err != 0 {
-		return error(err)
+		return cplError(err)
 	}
 
 	return nil
 }
 
+// Convert a NULL terminated list of C strings to a slice
+func goStringList(list **C.char) []string {
+	count := int(C.CSLCount(list))
+	if count == 0 {
+		return nil
+	}
+	values := make([]string, count)
+	for i := range values {
+		values[i] = C.GoString(C.CSLGetField(list, C.int(i)))
+	}
+	return values
+}
+
+// Convert a slice to a NULL terminated list of C strings, to be freed with
+// CSLDestroy
+func cStringList(list []string) **C.char {
+	var cList **C.char
+	for _, s := range list {
+		cString := C.CString(s)
+		cList = C.CSLAddString(cList, cString)
+		C.free(unsafe.Pointer(cString))
+	}
+	return cList
+}
+
+// Convert metadata to a NULL terminated list of NAME=VALUE strings, to be
+// freed with CSLDestroy.  The list is sorted, so that the stored order does
+// not depend on map iteration.
+func cMetadataList(metadata map[string]string) **C.char {
+	names := make([]string, 0, len(metadata))
+	for name := range metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + metadata[name]
+	}
+	return cStringList(pairs)
+}
+
 func metadata(object unsafe.Pointer, domain string) map[string]string {
+	if isDocumentDomain(domain) {
+		return nil
+	}
+
 	c_domain := C.CString(domain)
 	defer C.free(unsafe.Pointer(c_domain))
 
@@ -1817,23 +2324,64 @@ func metadata(object unsafe.Pointer, domain string) map[string]string {
 	stringCount := C.CSLCount(stringList)
 	metadata := make(map[string]string, stringCount)
 
-	var nameBuffer []byte
 	for i := (C.int)(0); i < stringCount; i++ {
-		cPair := C.CSLGetField(stringList, i)
-		totalLength := C.strlen(cPair) + 1 // add one for null terminator
-		if int(totalLength) > len(nameBuffer) {
-			nameBuffer = make([]byte, totalLength)
+		var cName *C.char
+		cValue := C.CPLParseNameValue(C.CSLGetField(stringList, i), &cName)
+		if cName == nil {
+			continue
 		}
+		metadata[C.GoString(cName)] = C.GoString(cValue)
+		C.VSIFree(unsafe.Pointer(cName))
+	}
 
-		namePointer := (*C.char)(unsafe.Pointer(&nameBuffer[0]))
-		cValue := C.CPLParseNameValue(cPair, &namePointer)
+	return metadata
+}
 
-		name := C.GoString(namePointer)
-		value := C.GoString(cValue)
-		metadata[name] = value
+func setMetadata(object unsafe.Pointer, metadata map[string]string, domain string) error {
+	if isDocumentDomain(domain) {
+		return newError(CPLE_IllegalArg, "Error: domain %q holds a document, use SetMetadataDocument", domain)
 	}
 
-	return metadata
+	stringList := cMetadataList(metadata)
+	defer C.CSLDestroy(stringList)
+
+	c_domain := C.CString(domain)
+	defer C.free(unsafe.Pointer(c_domain))
+
+	defer captureErrors()()
+	err := C.GDALSetMetadata((C.GDALMajorObjectH)(object), stringList, c_domain)
+	if err != 0 {
+		return cplError(err)
+	}
+
+	return nil
+}
+
+func metadataDocument(object unsafe.Pointer, domain string) string {
+	c_domain := C.CString(domain)
+	defer C.free(unsafe.Pointer(c_domain))
+
+	stringList := C.GDALGetMetadata((C.GDALMajorObjectH)(object), c_domain)
+	if stringList == nil {
+		return ""
+	}
+	return C.GoString(C.CSLGetField(stringList, 0))
+}
+
+func setMetadataDocument(object unsafe.Pointer, document, domain string) error {
+	stringList := cStringList([]string{document})
+	defer C.CSLDestroy(stringList)
+
+	c_domain := C.CString(domain)
+	defer C.free(unsafe.Pointer(c_domain))
+
+	defer captureErrors()()
+	err := C.GDALSetMetadata((C.GDALMajorObjectH)(object), stringList, c_domain)
+	if err != 0 {
+		return cplError(err)
+	}
+
+	return nil
 }
 
 func description(object unsafe.Pointer) string {
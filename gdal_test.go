@@ -4,7 +4,11 @@
 
 package gdal
 
-import "testing"
+import (
+	"errors"
+	"math"
+	"testing"
+)
 
 func TestTiffDriver(t *testing.T) {
 	_, err := GetDriverByName("GTiff")
@@ -12,3 +16,278 @@ func TestTiffDriver(t *testing.T) {
 		t.Errorf(err.Error())
 	}
 }
+
+// Create an in-memory raster with a single band
+func createMemoryRaster(t *testing.T, xSize, ySize int, dataType DataType) Dataset {
+	driver, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return driver.Create("", xSize, ySize, 1, dataType, nil)
+}
+
+func TestReadWriteWindow(t *testing.T) {
+	dataset := createMemoryRaster(t, 4, 4, Float32)
+	defer dataset.Close()
+	band := dataset.RasterBand(1)
+
+	data := make([]float32, 16)
+	for i := range data {
+		data[i] = float32(i)
+	}
+	if err := WriteWindow(band, 0, 0, 4, 4, data); err != nil {
+		t.Fatalf("WriteWindow: %v", err)
+	}
+
+	window, err := ReadWindow[float32](band, 1, 1, 2, 2)
+	if err != nil {
+		t.Fatalf("ReadWindow: %v", err)
+	}
+	want := []float32{5, 6, 9, 10}
+	for i := range want {
+		if window[i] != want[i] {
+			t.Errorf("ReadWindow: got %v, want %v", window, want)
+			break
+		}
+	}
+
+	// nearest neighbour resampling picks the pixel at each cell center
+	resampled, err := ReadWindowResampled[int32](band, 0, 0, 4, 4, 2, 2)
+	if err != nil {
+		t.Fatalf("ReadWindowResampled: %v", err)
+	}
+	wantResampled := []int32{5, 7, 13, 15}
+	for i := range wantResampled {
+		if resampled[i] != wantResampled[i] {
+			t.Errorf("ReadWindowResampled: got %v, want %v", resampled, wantResampled)
+			break
+		}
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"window outside of raster", func() error {
+			_, err := ReadWindow[uint8](band, 3, 3, 2, 2)
+			return err
+		}},
+		{"empty window", func() error {
+			_, err := ReadWindow[uint8](band, 0, 0, 0, 4)
+			return err
+		}},
+		{"short buffer", func() error {
+			return WriteWindow(band, 0, 0, 4, 4, make([]float64, 15))
+		}},
+		{"empty buffer", func() error {
+			return band.IO(Read, 0, 0, 1, 1, []uint8{}, 1, 1, 0, 0)
+		}},
+		{"negative pixel spacing", func() error {
+			return band.IO(Read, 0, 0, 4, 1, []float32{0}, 4, 1, -4, 0)
+		}},
+		{"negative line spacing", func() error {
+			return band.IO(Read, 0, 0, 1, 4, []float32{}, 1, 4, 0, -4)
+		}},
+		{"negative band spacing", func() error {
+			return dataset.IO(Read, 0, 0, 4, 4, []float32{0}, 4, 4, 1, nil, 0, 0, -64)
+		}},
+		{"no band", func() error {
+			return dataset.IO(Read, 0, 0, 4, 4, []float32{}, 4, 4, 0, nil, 0, 0, 0)
+		}},
+		{"negative band count", func() error {
+			return dataset.IO(Read, 0, 0, 4, 4, []float32{0}, 4, 4, -1, nil, 0, 0, 64)
+		}},
+		{"invalid buffer type", func() error {
+			return band.IO(Read, 0, 0, 1, 1, []string{""}, 1, 1, 0, 0)
+		}},
+	}
+	for _, test := range tests {
+		err := test.call()
+		if !errors.Is(err, CPLE_IllegalArg) {
+			t.Errorf("%s: got %v, want illegal argument", test.name, err)
+		}
+	}
+}
+
+func TestRuntimeVersion(t *testing.T) {
+	if !CheckVersion(VERSION_MAJOR, VERSION_MINOR) {
+		t.Errorf("runtime GDAL %s is not compatible with %s", RuntimeReleaseName(), RELEASE_NAME)
+	}
+	if num := RuntimeVersionNum(); num < VERSION_NUM {
+		t.Errorf("runtime version %d is older than build version %d", num, VERSION_NUM)
+	}
+
+	found := false
+	for _, name := range DriverNames() {
+		if name == "MEM" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("MEM driver not listed")
+	}
+
+	if Supports(BuildFeature("no such feature")) {
+		t.Error("unknown feature reported as supported")
+	}
+}
+
+func TestGCPs(t *testing.T) {
+	dataset := createMemoryRaster(t, 10, 10, Byte)
+	defer dataset.Close()
+
+	// pixel (0,0) at (100,200), 2 units per pixel, north up
+	gcps := []GCP{
+		{ID: "1", Info: "upper left", Pixel: 0, Line: 0, X: 100, Y: 200},
+		{ID: "2", Pixel: 10, Line: 0, X: 120, Y: 200},
+		{ID: "3", Pixel: 0, Line: 10, X: 100, Y: 180},
+		{ID: "4", Pixel: 10, Line: 10, X: 120, Y: 180, Z: 5},
+	}
+	srs := CreateSpatialReference("")
+	defer srs.Destroy()
+	if err := srs.FromEPSG(4326); err != nil {
+		t.Fatal(err)
+	}
+	wkt, err := srs.ToWKT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dataset.SetGCPs(gcps, wkt); err != nil {
+		t.Fatalf("SetGCPs: %v", err)
+	}
+
+	if count := dataset.GDALGetGCPCount(); count != len(gcps) {
+		t.Errorf("GCP count: got %d, want %d", count, len(gcps))
+	}
+	got := dataset.GCPs()
+	if len(got) != len(gcps) {
+		t.Fatalf("GCPs: got %v, want %v", got, gcps)
+	}
+	for i := range gcps {
+		if got[i] != gcps[i] {
+			t.Errorf("GCP %d: got %+v, want %+v", i, got[i], gcps[i])
+		}
+	}
+	if dataset.GCPProjection() == "" {
+		t.Error("GCP projection not set")
+	}
+
+	transform, err := GCPsToGeoTransform(got, false)
+	if err != nil {
+		t.Fatalf("GCPsToGeoTransform: %v", err)
+	}
+	want := [6]float64{100, 2, 0, 200, 0, -2}
+	for i := range want {
+		if math.Abs(transform[i]-want[i]) > 1e-9 {
+			t.Errorf("GCPsToGeoTransform: got %v, want %v", transform, want)
+			break
+		}
+	}
+
+	if _, err := GCPsToGeoTransform(nil, true); err == nil {
+		t.Error("GCPsToGeoTransform: no error without GCPs")
+	}
+}
+
+func TestGeoTransform(t *testing.T) {
+	dataset := createMemoryRaster(t, 10, 10, Byte)
+	defer dataset.Close()
+
+	if gt, ok := dataset.GeoTransform(); ok || gt != DefaultGeoTransform {
+		t.Errorf("GeoTransform of new dataset: got %v, %v", gt, ok)
+	}
+	gt := GeoTransform{100, 2, 0, 200, 0, -2}
+	if err := dataset.SetGeoTransform(gt); err != nil {
+		t.Fatalf("SetGeoTransform: %v", err)
+	}
+	if got, ok := dataset.GeoTransform(); !ok || got != gt {
+		t.Errorf("GeoTransform: got %v, %v, want %v", got, ok, gt)
+	}
+
+	if !gt.IsNorthUp() {
+		t.Error("IsNorthUp: got false")
+	}
+	if x, y := gt.PixelToWorld(5, 5); x != 110 || y != 190 {
+		t.Errorf("PixelToWorld: got (%v, %v), want (110, 190)", x, y)
+	}
+	pixel, line, err := gt.WorldToPixel(110, 190)
+	if err != nil {
+		t.Fatalf("WorldToPixel: %v", err)
+	}
+	if math.Abs(pixel-5) > 1e-9 || math.Abs(line-5) > 1e-9 {
+		t.Errorf("WorldToPixel: got (%v, %v), want (5, 5)", pixel, line)
+	}
+
+	var env Envelope
+	env.SetMinX(103)
+	env.SetMaxX(108)
+	env.SetMinY(190)
+	env.SetMaxY(196)
+	xOff, yOff, xSize, ySize, err := gt.Window(env)
+	if err != nil {
+		t.Fatalf("Window: %v", err)
+	}
+	if xOff != 1 || yOff != 2 || xSize != 3 || ySize != 3 {
+		t.Errorf("Window: got %d,%d %dx%d, want 1,2 3x3", xOff, yOff, xSize, ySize)
+	}
+
+	if _, err := (GeoTransform{}).Invert(); err == nil {
+		t.Error("Invert: no error for degenerate transformation")
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	dataset := createMemoryRaster(t, 1, 1, Byte)
+	defer dataset.Close()
+
+	want := map[string]string{"AREA_OR_POINT": "Area", "SENSOR": "test"}
+	if err := dataset.SetMetadata(want, ""); err != nil {
+		t.Fatalf("SetMetadata: %v", err)
+	}
+	if err := dataset.SetMetadataItem("BAND", "red", "custom"); err != nil {
+		t.Fatalf("SetMetadataItem: %v", err)
+	}
+
+	got := dataset.Metadata("")
+	if len(got) != len(want) {
+		t.Errorf("Metadata: got %v, want %v", got, want)
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("Metadata %s: got %q, want %q", name, got[name], value)
+		}
+	}
+	if value := dataset.MetadataItem("BAND", "custom"); value != "red" {
+		t.Errorf("MetadataItem: got %q, want %q", value, "red")
+	}
+
+	if VERSION_NUM >= 1110000 {
+		domains, err := dataset.MetadataDomains()
+		if err != nil {
+			t.Fatalf("MetadataDomains: %v", err)
+		}
+		found := make(map[string]bool)
+		for _, domain := range domains {
+			found[domain] = true
+		}
+		if !found[""] || !found["custom"] {
+			t.Errorf("MetadataDomains: got %q, want \"\" and \"custom\"", domains)
+		}
+	}
+
+	document := `<root><item key="a=b"/></root>`
+	if err := dataset.SetMetadataDocument(document, "xml:test"); err != nil {
+		t.Fatalf("SetMetadataDocument: %v", err)
+	}
+	if got := dataset.MetadataDocument("xml:test"); got != document {
+		t.Errorf("MetadataDocument: got %q, want %q", got, document)
+	}
+	if err := dataset.SetMetadata(want, "xml:test"); !errors.Is(err, CPLE_IllegalArg) {
+		t.Errorf("SetMetadata on document domain: got %v, want illegal argument", err)
+	}
+
+	driver := dataset.Driver()
+	if name := driver.MetadataItem("DMD_LONGNAME", ""); name != driver.LongName() {
+		t.Errorf("driver MetadataItem: got %q, want %q", name, driver.LongName())
+	}
+}
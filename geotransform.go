@@ -0,0 +1,124 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+*/
+import "C"
+import (
+	"math"
+	"unsafe"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Affine transformations.                                         */
+/* -------------------------------------------------------------------- */
+
+// Affine transformation from pixel/line to georeferenced coordinates:
+//
+//	x = gt[0] + pixel*gt[1] + line*gt[2]
+//	y = gt[3] + pixel*gt[4] + line*gt[5]
+//
+// where (pixel, line) = (0, 0) is the top left corner of the top left pixel.
+type GeoTransform [6]float64
+
+// Transformation GDAL assumes for datasets without one, mapping pixel/line
+// coordinates to themselves
+var DefaultGeoTransform = GeoTransform{0, 1, 0, 0, 0, 1}
+
+// Apply the transformation to a pixel/line position
+func (gt GeoTransform) Apply(pixel, line float64) (x, y float64) {
+	var cX, cY C.double
+	C.GDALApplyGeoTransform(
+		(*C.double)(unsafe.Pointer(&gt[0])),
+		C.double(pixel),
+		C.double(line),
+		&cX,
+		&cY,
+	)
+	return float64(cX), float64(cY)
+}
+
+// Compute the inverse transformation, mapping georeferenced coordinates to
+// pixel/line.  It fails if the transformation is degenerate.
+func (gt GeoTransform) Invert() (GeoTransform, error) {
+	var inverse GeoTransform
+	defer captureErrors()()
+	ok := C.GDALInvGeoTransform(
+		(*C.double)(unsafe.Pointer(&gt[0])),
+		(*C.double)(unsafe.Pointer(&inverse[0])),
+	)
+	if ok == 0 {
+		return inverse, lastError(CPLE_IllegalArg, "Error: geotransform %v is not invertible", gt)
+	}
+	return inverse, nil
+}
+
+// Fetch the georeferenced coordinates of a pixel/line position.  This is
+// the same as Apply; the center of pixel (i, j) is at (i+0.5, j+0.5).
+func (gt GeoTransform) PixelToWorld(pixel, line float64) (x, y float64) {
+	return gt.Apply(pixel, line)
+}
+
+// Fetch the pixel/line position of georeferenced coordinates
+func (gt GeoTransform) WorldToPixel(x, y float64) (pixel, line float64, err error) {
+	inverse, err := gt.Invert()
+	if err != nil {
+		return 0, 0, err
+	}
+	pixel, line = inverse.Apply(x, y)
+	return pixel, line, nil
+}
+
+// Report whether rows run along the X axis from north to south, without
+// rotation
+func (gt GeoTransform) IsNorthUp() bool {
+	return gt[2] == 0 && gt[4] == 0 && gt[5] < 0
+}
+
+// Fetch the georeferenced coordinates of the top left corner of the raster
+func (gt GeoTransform) Origin() (x, y float64) {
+	return gt[0], gt[3]
+}
+
+// Fetch the pixel width and height; the height is negative for north up
+// rasters
+func (gt GeoTransform) PixelSize() (width, height float64) {
+	return gt[1], gt[5]
+}
+
+// Fetch the rotation terms, both zero unless the raster is rotated or
+// sheared
+func (gt GeoTransform) Rotation() (row, column float64) {
+	return gt[2], gt[4]
+}
+
+// Compute the smallest pixel window covering the envelope.  The window is
+// not clipped to the raster and may lie partly or entirely outside of it.
+func (gt GeoTransform) Window(env Envelope) (xOff, yOff, xSize, ySize int, err error) {
+	inverse, err := gt.Invert()
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	minPixel, minLine := math.Inf(1), math.Inf(1)
+	maxPixel, maxLine := math.Inf(-1), math.Inf(-1)
+	corners := [4][2]float64{
+		{env.MinX(), env.MinY()},
+		{env.MinX(), env.MaxY()},
+		{env.MaxX(), env.MinY()},
+		{env.MaxX(), env.MaxY()},
+	}
+	for _, corner := range corners {
+		pixel, line := inverse.Apply(corner[0], corner[1])
+		minPixel, maxPixel = math.Min(minPixel, pixel), math.Max(maxPixel, pixel)
+		minLine, maxLine = math.Min(minLine, line), math.Max(maxLine, line)
+	}
+
+	// tolerate rounding errors of envelopes aligned with pixel edges
+	const epsilon = 1e-8
+	xOff = int(math.Floor(minPixel + epsilon))
+	yOff = int(math.Floor(minLine + epsilon))
+	xSize = int(math.Ceil(maxPixel-epsilon)) - xOff
+	ySize = int(math.Ceil(maxLine-epsilon)) - yOff
+	return xOff, yOff, xSize, ySize, nil
+}
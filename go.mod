@@ -0,0 +1,3 @@
+module github.com/kikht/gdal
+
+go 1.21
@@ -6,19 +6,298 @@
 #include "_cgo_export.h"
 
 #include <cpl_conv.h>
+#include <errno.h>
+#include <stdio.h>
+#include <string.h>
+#include <sys/stat.h>
 
-static int goGDALProgressFuncProxyB_(
-	double complete, 
-	const char *message, 
+static int CPL_STDCALL goGDALProgressFuncProxyB_(
+	double complete,
+	const char *message,
 	void *progressArg
 ) {
-	GoInterface* args = (GoInterface*)progressArg;
-	int returnVal = goGDALProgressFuncProxyA(complete, (char*)message, args);
-	return (int)returnVal;
+	return goGDALProgressFuncProxyA(complete, (char*)message, (uintptr_t)progressArg);
 }
 
 GDALProgressFunc goGDALProgressFuncProxyB() {
 	return goGDALProgressFuncProxyB_;
 }
 
+char **goGDALGetMetadataDomainList(GDALMajorObjectH object) {
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(1,11,0)
+	return GDALGetMetadataDomainList(object);
+#else
+	CPLError(CE_Failure, CPLE_NotSupported,
+		"GDALGetMetadataDomainList() requires GDAL 1.11 or newer");
+	return NULL;
+#endif
+}
+
+#define GO_GDAL_ERROR_MSG_SIZE 2048
+
+static __thread CPLErr goGDALErrClass = CE_None;
+static __thread int goGDALErrNum = CPLE_None;
+static __thread char goGDALErrMsg[GO_GDAL_ERROR_MSG_SIZE];
+
+static void goGDALErrorHandler(CPLErr errClass, int errNum, const char *msg) {
+	if (errClass < CE_Failure) {
+		CPLDefaultErrorHandler(errClass, errNum, msg);
+		return;
+	}
+	// keep the first error of the highest severity, it is usually the cause
+	if (errClass > goGDALErrClass) {
+		goGDALErrClass = errClass;
+		goGDALErrNum = errNum;
+		snprintf(goGDALErrMsg, GO_GDAL_ERROR_MSG_SIZE, "%s", msg);
+	}
+}
+
+void goGDALInstallErrorHandler() {
+	CPLSetErrorHandler(goGDALErrorHandler);
+}
+
+void goGDALErrorReset() {
+	goGDALErrClass = CE_None;
+	goGDALErrNum = CPLE_None;
+	goGDALErrMsg[0] = '\0';
+	CPLErrorReset();
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(2,2,0)
+	VSIErrorReset();
+#endif
+}
+
+CPLErr goGDALLastError(int *errNum, const char **msg) {
+	*errNum = goGDALErrNum;
+	*msg = goGDALErrMsg;
+	return goGDALErrClass;
+}
+
+VSILFILE *goVSIFOpen(const char *filename, const char *access) {
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(2,1,0)
+	return VSIFOpenExL(filename, access, TRUE);
+#else
+	return VSIFOpenL(filename, access);
+#endif
+}
+
+int goVSILastError(const char **msg) {
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(2,2,0)
+	*msg = VSIGetLastErrorMsg();
+	return VSIGetLastErrorNo();
+#else
+	*msg = "";
+	return 0;
+#endif
+}
+
+void goGDALReportError(int errNum, const char *msg) {
+	CPLError(CE_Failure, errNum, "%s", msg);
+}
+
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3,0,0)
+
+// status returned by the Go side of plugin calls
+#define GO_VSI_OK 0
+#define GO_VSI_NOT_EXIST 1
+
+static int goVSIPluginStat_(void *plugin, const char *filename, VSIStatBufL *statBuf, int flags) {
+	long long size = 0, mtime = 0;
+	int isDir = 0;
+	int status = goVSIPluginStat((uintptr_t)plugin, (char*)filename, &size, &isDir, &mtime);
+	if (status != GO_VSI_OK) {
+		if (status == GO_VSI_NOT_EXIST) {
+			errno = ENOENT;
+		}
+		return -1;
+	}
+	memset(statBuf, 0, sizeof(VSIStatBufL));
+	statBuf->st_size = size;
+	statBuf->st_mode = isDir ? S_IFDIR | 0555 : S_IFREG | 0444;
+	statBuf->st_mtime = mtime;
+	return 0;
+}
+
+static char **goVSIPluginReadDir_(void *plugin, const char *dirname, int maxFiles) {
+	return goVSIPluginReadDir((uintptr_t)plugin, (char*)dirname, maxFiles);
+}
+
+static void *goVSIPluginOpen_(void *plugin, const char *filename, const char *access) {
+	int status = GO_VSI_OK;
+	uintptr_t file = goVSIPluginOpen((uintptr_t)plugin, (char*)filename, (char*)access, &status);
+	if (status == GO_VSI_NOT_EXIST) {
+		errno = ENOENT;
+	}
+	return (void*)file;
+}
+
+static vsi_l_offset goVSIPluginTell_(void *file) {
+	return goVSIPluginTell((uintptr_t)file);
+}
+
+static int goVSIPluginSeek_(void *file, vsi_l_offset offset, int whence) {
+	return goVSIPluginSeek((uintptr_t)file, offset, whence);
+}
+
+static size_t goVSIPluginRead_(void *file, void *buffer, size_t size, size_t count) {
+	if (size == 0 || count == 0) {
+		return 0;
+	}
+	return goVSIPluginRead((uintptr_t)file, buffer, size * count) / size;
+}
 
+static int goVSIPluginEof_(void *file) {
+	return goVSIPluginEof((uintptr_t)file);
+}
+
+static int goVSIPluginClose_(void *file) {
+	return goVSIPluginClose((uintptr_t)file);
+}
+
+int goVSIInstallPlugin(const char *prefix, uintptr_t plugin) {
+	VSIFilesystemPluginCallbacksStruct *callbacks = VSIAllocFilesystemPluginCallbacksStruct();
+	callbacks->pUserData = (void*)plugin;
+	callbacks->stat = goVSIPluginStat_;
+	callbacks->read_dir = goVSIPluginReadDir_;
+	callbacks->open = goVSIPluginOpen_;
+	callbacks->tell = goVSIPluginTell_;
+	callbacks->seek = goVSIPluginSeek_;
+	callbacks->read = goVSIPluginRead_;
+	callbacks->eof = goVSIPluginEof_;
+	callbacks->close = goVSIPluginClose_;
+	int ret = VSIInstallPluginHandler(prefix, callbacks);
+	VSIFreeFilesystemPluginCallbacksStruct(callbacks);
+	return ret;
+}
+
+#else
+
+int goVSIInstallPlugin(const char *prefix, uintptr_t plugin) {
+	CPLError(CE_Failure, CPLE_NotSupported,
+		"Go filesystem plugins require GDAL 3.0 or newer");
+	return -1;
+}
+
+#endif
+
+// GDALRPCInfo is GDALRPCInfoV2 through macros since GDAL 2.3
+void *goGDALCreateRPCTransformer(char **rpcMetadata, int reversed, double pixErrThreshold, char **options) {
+	GDALRPCInfo rpc;
+	if (!GDALExtractRPCInfo(rpcMetadata, &rpc)) {
+		CPLError(CE_Failure, CPLE_AppDefined, "Invalid or incomplete RPC metadata");
+		return NULL;
+	}
+	return GDALCreateRPCTransformer(&rpc, reversed, pixErrThreshold, options);
+}
+
+static CPLErr goGDALContourWriter_(double level, int count, double *x, double *y, void *writer) {
+	return (CPLErr)goGDALContourWriter(level, count, x, y, (uintptr_t)writer);
+}
+
+GDALContourGeneratorH goGDALCreateContourGenerator(int width, int height, int useNoData, double noData, double interval, double base, uintptr_t writer) {
+	return GDAL_CG_Create(width, height, useNoData, noData, interval, base, goGDALContourWriter_, (void*)writer);
+}
+
+CPLErr goGDALContourGenerateEx(GDALRasterBandH band, void *layer, char **options, GDALProgressFunc progress, void *progressArg) {
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(2,4,0)
+	return GDALContourGenerateEx(band, layer, options, progress, progressArg);
+#else
+	CPLError(CE_Failure, CPLE_NotSupported,
+		"Contour polygons and ELEV_FIELD_MIN/MAX require GDAL 2.4 or newer");
+	return CE_Failure;
+#endif
+}
+
+// allocate zeroed grid options, to be freed with VSIFree
+static void *goGDALAllocGridOptions(size_t size) {
+	void *options = VSICalloc(1, size);
+	if (options == NULL) {
+		CPLError(CE_Failure, CPLE_OutOfMemory, "Cannot allocate grid options");
+	}
+	return options;
+}
+
+// options structures record their size since GDAL 3.6
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3,6,0)
+#define GO_GDAL_GRID_OPTIONS(type) \
+	type *options = (type *)goGDALAllocGridOptions(sizeof(type)); \
+	if (options == NULL) return NULL; \
+	options->nSizeOfStructure = sizeof(type)
+#else
+#define GO_GDAL_GRID_OPTIONS(type) \
+	type *options = (type *)goGDALAllocGridOptions(sizeof(type)); \
+	if (options == NULL) return NULL
+#endif
+
+void *goGDALGridInverseDistanceOptions(double power, double smoothing, double anisotropyRatio, double anisotropyAngle, double radius1, double radius2, double angle, GUInt32 maxPoints, GUInt32 minPoints, double noData) {
+	GO_GDAL_GRID_OPTIONS(GDALGridInverseDistanceToAPowerOptions);
+	options->dfPower = power;
+	options->dfSmoothing = smoothing;
+	options->dfAnisotropyRatio = anisotropyRatio;
+	options->dfAnisotropyAngle = anisotropyAngle;
+	options->dfRadius1 = radius1;
+	options->dfRadius2 = radius2;
+	options->dfAngle = angle;
+	options->nMaxPoints = maxPoints;
+	options->nMinPoints = minPoints;
+	options->dfNoDataValue = noData;
+	return options;
+}
+
+void *goGDALGridInverseDistanceNNOptions(double power, double smoothing, double radius, GUInt32 maxPoints, GUInt32 minPoints, double noData) {
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(2,1,0)
+	GO_GDAL_GRID_OPTIONS(GDALGridInverseDistanceToAPowerNearestNeighborOptions);
+	options->dfPower = power;
+	options->dfSmoothing = smoothing;
+	options->dfRadius = radius;
+	options->nMaxPoints = maxPoints;
+	options->nMinPoints = minPoints;
+	options->dfNoDataValue = noData;
+	return options;
+#else
+	CPLError(CE_Failure, CPLE_NotSupported,
+		"Inverse distance to a power with nearest neighbour search requires GDAL 2.1 or newer");
+	return NULL;
+#endif
+}
+
+void *goGDALGridMovingAverageOptions(double radius1, double radius2, double angle, GUInt32 minPoints, double noData) {
+	GO_GDAL_GRID_OPTIONS(GDALGridMovingAverageOptions);
+	options->dfRadius1 = radius1;
+	options->dfRadius2 = radius2;
+	options->dfAngle = angle;
+	options->nMinPoints = minPoints;
+	options->dfNoDataValue = noData;
+	return options;
+}
+
+void *goGDALGridNearestNeighborOptions(double radius1, double radius2, double angle, double noData) {
+	GO_GDAL_GRID_OPTIONS(GDALGridNearestNeighborOptions);
+	options->dfRadius1 = radius1;
+	options->dfRadius2 = radius2;
+	options->dfAngle = angle;
+	options->dfNoDataValue = noData;
+	return options;
+}
+
+void *goGDALGridDataMetricsOptions(double radius1, double radius2, double angle, GUInt32 minPoints, double noData) {
+	GO_GDAL_GRID_OPTIONS(GDALGridDataMetricsOptions);
+	options->dfRadius1 = radius1;
+	options->dfRadius2 = radius2;
+	options->dfAngle = angle;
+	options->nMinPoints = minPoints;
+	options->dfNoDataValue = noData;
+	return options;
+}
+
+void *goGDALGridLinearOptions(double radius, double noData) {
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(2,1,0)
+	GO_GDAL_GRID_OPTIONS(GDALGridLinearOptions);
+	options->dfRadius = radius;
+	options->dfNoDataValue = noData;
+	return options;
+#else
+	CPLError(CE_Failure, CPLE_NotSupported,
+		"Linear interpolation requires GDAL 2.1 or newer");
+	return NULL;
+#endif
+}
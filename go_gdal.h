@@ -9,11 +9,102 @@
 #include <gdal_alg.h>
 #include <gdalwarper.h>
 #include <cpl_conv.h>
+#include <cpl_error.h>
 #include <ogr_srs_api.h>
 #include <cpl_vsi.h>
+#include <stdint.h>
+
+// oldest supported version; GDAL_COMPUTE_VERSION itself appeared in 1.10
+#define GO_GDAL_MIN_VERSION_MAJOR 1
+#define GO_GDAL_MIN_VERSION_MINOR 10
+#ifndef GDAL_COMPUTE_VERSION
+#error "The gdal Go package requires GDAL 1.10 or newer, but older GDAL headers were found. Point pkg-config (PKG_CONFIG_PATH) or CGO_CFLAGS at a newer GDAL installation."
+// keep the version checks below from burying the message in syntax errors
+#define GDAL_COMPUTE_VERSION(maj,min,rev) 0
+#elif GDAL_VERSION_NUM < GDAL_COMPUTE_VERSION(GO_GDAL_MIN_VERSION_MAJOR,GO_GDAL_MIN_VERSION_MINOR,0)
+#error "The gdal Go package requires GDAL 1.10 or newer, but older GDAL headers were found. Point pkg-config (PKG_CONFIG_PATH) or CGO_CFLAGS at a newer GDAL installation."
+#endif
+
+// VSIStatExL ignores flags it does not know, such as this one before 2.2
+#ifndef VSI_STAT_SET_ERROR_FLAG
+#define VSI_STAT_SET_ERROR_FLAG 0x8
+#endif
+
+// feature ids are long before GDAL 2.0 and GIntBig since
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(2,0,0)
+typedef GIntBig goGDALFID;
+#else
+typedef long goGDALFID;
+#endif
+
+// added in GDAL 2.0
+#ifndef OGRERR_NON_EXISTING_FEATURE
+#define OGRERR_NON_EXISTING_FEATURE 9
+#endif
+
+// data types added in GDAL 3.5 and 3.7, unknown to older versions
+#if GDAL_VERSION_NUM < GDAL_COMPUTE_VERSION(3,5,0)
+#define GDT_UInt64 GDT_Unknown
+#define GDT_Int64 GDT_Unknown
+#endif
+#if GDAL_VERSION_NUM < GDAL_COMPUTE_VERSION(3,7,0)
+#define GDT_Int8 GDT_Unknown
+#endif
+
+// grid algorithms added in GDAL 2.1
+#if GDAL_VERSION_NUM < GDAL_COMPUTE_VERSION(2,1,0)
+#define GGA_Linear 10
+#define GGA_InverseDistanceToAPowerNearestNeighbor 11
+#endif
+
+// added in GDAL 1.11, raises CPLE_NotSupported with older versions
+char **goGDALGetMetadataDomainList(GDALMajorObjectH object);
 
 // transform GDALProgressFunc to go func
 GDALProgressFunc goGDALProgressFuncProxyB();
+// pass a cgo.Handle as the void* argument of a callback
+static inline void *goGDALHandleArg(uintptr_t handle) { return (void *)handle; }
+
+// transformer function for any transformer created by GDAL, which records
+// its own function in its argument
+static inline GDALTransformerFunc goGDALUseTransformer() { return GDALUseTransformer; }
+// create an RPC transformer from the RPC metadata domain, whatever the
+// GDALRPCInfo version of the headers
+void *goGDALCreateRPCTransformer(char **rpcMetadata, int reversed, double pixErrThreshold, char **options);
+
+// create a contour generator calling the Go writer behind a cgo.Handle
+GDALContourGeneratorH goGDALCreateContourGenerator(int width, int height, int useNoData, double noData, double interval, double base, uintptr_t writer);
+// generate contours from options, raises CPLE_NotSupported before GDAL 2.4
+CPLErr goGDALContourGenerateEx(GDALRasterBandH band, void *layer, char **options, GDALProgressFunc progress, void *progressArg);
+
+// allocate the options of a grid algorithm, to be freed with VSIFree; the
+// nearest neighbour inverse distance and linear ones raise
+// CPLE_NotSupported before GDAL 2.1
+void *goGDALGridInverseDistanceOptions(double power, double smoothing, double anisotropyRatio, double anisotropyAngle, double radius1, double radius2, double angle, GUInt32 maxPoints, GUInt32 minPoints, double noData);
+void *goGDALGridInverseDistanceNNOptions(double power, double smoothing, double radius, GUInt32 maxPoints, GUInt32 minPoints, double noData);
+void *goGDALGridMovingAverageOptions(double radius1, double radius2, double angle, GUInt32 minPoints, double noData);
+void *goGDALGridNearestNeighborOptions(double radius1, double radius2, double angle, double noData);
+void *goGDALGridDataMetricsOptions(double radius1, double radius2, double angle, GUInt32 minPoints, double noData);
+void *goGDALGridLinearOptions(double radius, double noData);
+
+// st_mtime may be a macro, which Go cannot access
+static inline long long goVSIStatMTime(const VSIStatBufL *statBuf) { return statBuf->st_mtime; }
+// open a file, recording failures in the VSI error state with GDAL 2.1+
+VSILFILE *goVSIFOpen(const char *filename, const char *access);
+// fetch the VSI error of the calling thread, always 0 before GDAL 2.2
+int goVSILastError(const char **msg);
+
+// install the Go filesystem behind a cgo.Handle under a prefix, GDAL 3.0+
+int goVSIInstallPlugin(const char *prefix, uintptr_t plugin);
+// raise a CE_Failure error from Go
+void goGDALReportError(int errNum, const char *msg);
+
+// install the error handler recording CPL errors per thread
+void goGDALInstallErrorHandler();
+// clear the CPL and VSI errors recorded for the calling thread
+void goGDALErrorReset();
+// fetch the most severe error recorded for the calling thread
+CPLErr goGDALLastError(int *errNum, const char **msg);
 
 #endif // GO_GDAL_H_
 
@@ -3,16 +3,11 @@ package gdal
 /*
 #include "go_gdal.h"
 #include "gdal_version.h"
-
-#cgo linux  CFLAGS: -I/usr/include/gdal
-#cgo linux  LDFLAGS: -lgdal
-#cgo darwin pkg-config: gdal
-#cgo windows LDFLAGS: -lgdal.dll
 */
 import "C"
 import (
-	"errors"
 	"reflect"
+	"runtime"
 	"time"
 	"unsafe"
 )
@@ -191,35 +186,52 @@ func BoolToCInt(in bool) (out C.int) {
 /* -------------------------------------------------------------------- */
 
 type Geometry struct {
-	cval C.OGRGeometryH
+	cval  C.OGRGeometryH
+	owner *owner
+}
+
+// Wrap a geometry handle owned by the caller
+func ownGeometry(cval C.OGRGeometryH) Geometry {
+	if cval == nil {
+		return Geometry{}
+	}
+	return Geometry{cval, own(func() { C.OGR_G_DestroyGeometry(cval) })}
 }
 
 //Create a geometry object from its well known binary representation
 func CreateFromWKB(wkb []uint8, srs SpatialReference, bytes int) (Geometry, error) {
+	defer runtime.KeepAlive(srs)
+	defer captureErrors()()
+	if len(wkb) == 0 {
+		return Geometry{}, ogrError(C.OGRERR_NOT_ENOUGH_DATA)
+	}
 	cString := (*C.uchar)(unsafe.Pointer(&wkb[0]))
-	var newGeom Geometry
-	err := C.OGR_G_CreateFromWkb(cString, srs.cval, &newGeom.cval, C.int(bytes))
-	return newGeom, error(err)
+	var newGeom C.OGRGeometryH
+	err := C.OGR_G_CreateFromWkb(cString, srs.cval, &newGeom, C.int(bytes))
+	return ownGeometry(newGeom), ogrError(err)
 }
 
 //Create a geometry object from its well known text representation
 func CreateFromWKT(wkt string, srs SpatialReference) (Geometry, error) {
+	defer runtime.KeepAlive(srs)
 	cString := C.CString(wkt)
 	defer C.free(unsafe.Pointer(cString))
-	var newGeom Geometry
-	err := C.OGR_G_CreateFromWkt(&cString, srs.cval, &newGeom.cval)
-	return newGeom, error(err)
+	var newGeom C.OGRGeometryH
+	defer captureErrors()()
+	err := C.OGR_G_CreateFromWkt(&cString, srs.cval, &newGeom)
+	return ownGeometry(newGeom), ogrError(err)
 }
 
-// Destroy geometry object
+// Destroy geometry object.  Geometries owned by a feature or by a geometry
+// container are left alone, and destroying a geometry twice does nothing.
 func (geometry Geometry) Destroy() {
-	C.OGR_G_DestroyGeometry(geometry.cval)
+	geometry.owner.release()
 }
 
 // Create an empty geometry of the desired type
 func Create(geomType GeometryType) Geometry {
 	geom := C.OGR_G_CreateGeometry(C.OGRwkbGeometryType(geomType))
-	return Geometry{geom}
+	return ownGeometry(geom)
 }
 
 // Stroke arc to linestring
@@ -242,58 +254,71 @@ func ApproximateArcAngles(
 		C.double(startAngle),
 		C.double(endAngle),
 		C.double(stepSizeDegrees))
-	return Geometry{geom}
+	return ownGeometry(geom)
 }
 
 // Convert to polygon
 func (geom Geometry) ForceToPolygon() Geometry {
+	defer runtime.KeepAlive(geom)
 	newGeom := C.OGR_G_ForceToPolygon(geom.cval)
-	return Geometry{newGeom}
+	geom.owner.disown()
+	return ownGeometry(newGeom)
 }
 
 // Convert to multipolygon
 func (geom Geometry) ForceToMultiPolygon() Geometry {
+	defer runtime.KeepAlive(geom)
 	newGeom := C.OGR_G_ForceToMultiPolygon(geom.cval)
-	return Geometry{newGeom}
+	geom.owner.disown()
+	return ownGeometry(newGeom)
 }
 
 // Convert to multipoint
 func (geom Geometry) ForceToMultiPoint() Geometry {
+	defer runtime.KeepAlive(geom)
 	newGeom := C.OGR_G_ForceToMultiPoint(geom.cval)
-	return Geometry{newGeom}
+	geom.owner.disown()
+	return ownGeometry(newGeom)
 }
 
 // Convert to multilinestring
 func (geom Geometry) ForceToMultiLineString() Geometry {
+	defer runtime.KeepAlive(geom)
 	newGeom := C.OGR_G_ForceToMultiLineString(geom.cval)
-	return Geometry{newGeom}
+	geom.owner.disown()
+	return ownGeometry(newGeom)
 }
 
 // Get the dimension of this geometry
 func (geom Geometry) Dimension() int {
+	defer runtime.KeepAlive(geom)
 	dim := C.OGR_G_GetDimension(geom.cval)
 	return int(dim)
 }
 
 // Get the dimension of the coordinates in this geometry
 func (geom Geometry) CoordinateDimension() int {
+	defer runtime.KeepAlive(geom)
 	dim := C.OGR_G_GetCoordinateDimension(geom.cval)
 	return int(dim)
 }
 
 // Set the dimension of the coordinates in this geometry
 func (geom Geometry) SetCoordinateDimension(dim int) {
+	defer runtime.KeepAlive(geom)
 	C.OGR_G_SetCoordinateDimension(geom.cval, C.int(dim))
 }
 
 // Create a copy of this geometry
 func (geom Geometry) Clone() Geometry {
+	defer runtime.KeepAlive(geom)
 	newGeom := C.OGR_G_Clone(geom.cval)
-	return Geometry{newGeom}
+	return ownGeometry(newGeom)
 }
 
 // Compute and return the bounding envelope for this geometry
 func (geom Geometry) Envelope() Envelope {
+	defer runtime.KeepAlive(geom)
 	var env Envelope
 	C.OGR_G_GetEnvelope(geom.cval, &env.cval)
 	return env
@@ -303,43 +328,55 @@ func (geom Geometry) Envelope() Envelope {
 
 // Assign a geometry from well known binary data
 func (geom Geometry) FromWKB(wkb []uint8, bytes int) error {
+	defer runtime.KeepAlive(geom)
+	defer captureErrors()()
+	if len(wkb) == 0 {
+		return ogrError(C.OGRERR_NOT_ENOUGH_DATA)
+	}
 	cString := (*C.uchar)(unsafe.Pointer(&wkb[0]))
 	err := C.OGR_G_ImportFromWkb(geom.cval, cString, C.int(bytes))
-	return error(err)
+	return ogrError(err)
 }
 
 // Convert a geometry to well known binary data
 func (geom Geometry) ToWKB(byteOrder ByteOrder) ([]uint8, error) {
+	defer runtime.KeepAlive(geom)
 	size := C.OGR_G_WkbSize(geom.cval)
 	wkb := make([]uint8, size)
+	defer captureErrors()()
 	err := C.OGR_G_ExportToWkb(geom.cval, C.OGRwkbByteOrder(byteOrder), (*C.uchar)(unsafe.Pointer(&wkb[0])))
 	if err != 0 {
-		return nil, error(err)
+		return nil, ogrError(err)
 	}
 	return wkb, nil
 }
 
 // Returns size of related binary representation
 func (geom Geometry) WKBSize() int {
+	defer runtime.KeepAlive(geom)
 	size := C.OGR_G_WkbSize(geom.cval)
 	return int(size)
 }
 
 // Assign geometry object from its well known text representation
 func (geom Geometry) FromWKT(wkt string) error {
+	defer runtime.KeepAlive(geom)
 	cString := C.CString(wkt)
 	defer C.free(unsafe.Pointer(cString))
+	defer captureErrors()()
 	err := C.OGR_G_ImportFromWkt(geom.cval, &cString)
-	return error(err)
+	return ogrError(err)
 }
 
 // Fetch geometry as WKT
 func (geom Geometry) ToWKT() (string, error) {
+	defer runtime.KeepAlive(geom)
 	var p *C.char
 	// GDAL docs say this *always* returns OGRERR_NONE (0)
+	defer captureErrors()()
 	err := C.OGR_G_ExportToWkt(geom.cval, &p)
 	if err != 0 {
-		return "", error(err)
+		return "", ogrError(err)
 	}
 	wkt := C.GoString(p)
 	defer C.OGRFree(unsafe.Pointer(p))
@@ -348,12 +385,14 @@ func (geom Geometry) ToWKT() (string, error) {
 
 // Fetch geometry type
 func (geom Geometry) Type() GeometryType {
+	defer runtime.KeepAlive(geom)
 	gt := C.OGR_G_GetGeometryType(geom.cval)
 	return GeometryType(gt)
 }
 
 // Fetch geometry name
 func (geom Geometry) Name() string {
+	defer runtime.KeepAlive(geom)
 	name := C.OGR_G_GetGeometryName(geom.cval)
 	return C.GoString(name)
 }
@@ -362,11 +401,13 @@ func (geom Geometry) Name() string {
 
 // Convert geometry to strictly 2D
 func (geom Geometry) FlattenTo2D() {
+	defer runtime.KeepAlive(geom)
 	C.OGR_G_FlattenTo2D(geom.cval)
 }
 
 // Force rings to be closed
 func (geom Geometry) CloseRings() {
+	defer runtime.KeepAlive(geom)
 	C.OGR_G_CloseRings(geom.cval)
 }
 
@@ -375,17 +416,19 @@ func CreateFromGML(gml string) Geometry {
 	cString := C.CString(gml)
 	defer C.free(unsafe.Pointer(cString))
 	geom := C.OGR_G_CreateFromGML(cString)
-	return Geometry{geom}
+	return ownGeometry(geom)
 }
 
 // Convert a geometry to GML format
 func (geom Geometry) ToGML() string {
+	defer runtime.KeepAlive(geom)
 	val := C.OGR_G_ExportToGML(geom.cval)
 	return C.GoString(val)
 }
 
 // Convert a geometry to GML format with options
 func (geom Geometry) ToGML_Ex(options []string) string {
+	defer runtime.KeepAlive(geom)
 	length := len(options)
 	opts := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -400,18 +443,21 @@ func (geom Geometry) ToGML_Ex(options []string) string {
 
 // Convert a geometry to KML format
 func (geom Geometry) ToKML() string {
+	defer runtime.KeepAlive(geom)
 	val := C.OGR_G_ExportToKML(geom.cval, nil)
 	return C.GoString(val)
 }
 
 // Convert a geometry to JSON format
 func (geom Geometry) ToJSON() string {
+	defer runtime.KeepAlive(geom)
 	val := C.OGR_G_ExportToJson(geom.cval)
 	return C.GoString(val)
 }
 
 // Convert a geometry to JSON format with options
 func (geom Geometry) ToJSON_ex(options []string) string {
+	defer runtime.KeepAlive(geom)
 	length := len(options)
 	opts := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -426,126 +472,176 @@ func (geom Geometry) ToJSON_ex(options []string) string {
 
 // Fetch the spatial reference associated with this geometry
 func (geom Geometry) SpatialReference() SpatialReference {
+	defer runtime.KeepAlive(geom)
 	spatialRef := C.OGR_G_GetSpatialReference(geom.cval)
-	return SpatialReference{spatialRef}
+	return SpatialReference{spatialRef, borrow(geom.owner)}
 }
 
 // Assign a spatial reference to this geometry
 func (geom Geometry) SetSpatialReference(spatialRef SpatialReference) {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(spatialRef)
 	C.OGR_G_AssignSpatialReference(geom.cval, spatialRef.cval)
 }
 
 // Apply coordinate transformation to geometry
 func (geom Geometry) Transform(ct CoordinateTransform) error {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(ct)
+	if geom.cval == nil {
+		return newError(CPLE_ObjectNull, "Error: geometry is not valid")
+	}
+	if ct.cval == nil {
+		return newError(CPLE_ObjectNull, "Error: coordinate transform is not valid")
+	}
+	defer captureErrors()()
 	err := C.OGR_G_Transform(geom.cval, ct.cval)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 	return nil
 }
 
 // Transform geometry to new spatial reference system
 func (geom Geometry) TransformTo(sr SpatialReference) error {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(sr)
+	if geom.cval == nil {
+		return newError(CPLE_ObjectNull, "Error: geometry is not valid")
+	}
+	if sr.cval == nil {
+		return newError(CPLE_ObjectNull, "Error: spatial reference is not valid")
+	}
+	if C.OGR_G_GetSpatialReference(geom.cval) == nil {
+		return newError(CPLE_AppDefined, "Error: geometry has no spatial reference to transform from")
+	}
+	defer captureErrors()()
 	err := C.OGR_G_TransformTo(geom.cval, sr.cval)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 	return nil
 }
 
 // Simplify the geometry
 func (geom Geometry) Simplify(tolerance float64) Geometry {
+	defer runtime.KeepAlive(geom)
 	newGeom := C.OGR_G_Simplify(geom.cval, C.double(tolerance))
-	return Geometry{newGeom}
+	return ownGeometry(newGeom)
 }
 
 // Simplify the geometry while preserving topology
 func (geom Geometry) SimplifyPreservingTopology(tolerance float64) Geometry {
+	defer runtime.KeepAlive(geom)
 	newGeom := C.OGR_G_SimplifyPreserveTopology(geom.cval, C.double(tolerance))
-	return Geometry{newGeom}
+	return ownGeometry(newGeom)
 }
 
 // Modify the geometry such that it has no line segment longer than the given distance
 func (geom Geometry) Segmentize(distance float64) {
+	defer runtime.KeepAlive(geom)
 	C.OGR_G_Segmentize(geom.cval, C.double(distance))
 }
 
 // Return true if these features intersect
 func (geom Geometry) Intersects(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
 	val := C.OGR_G_Intersects(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if these features are equal
 func (geom Geometry) Equals(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
 	val := C.OGR_G_Equals(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if the features are disjoint
 func (geom Geometry) Disjoint(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
 	val := C.OGR_G_Disjoint(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if this feature touches the other
 func (geom Geometry) Touches(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
 	val := C.OGR_G_Touches(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if this feature crosses the other
 func (geom Geometry) Crosses(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
 	val := C.OGR_G_Crosses(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if this geometry is within the other
 func (geom Geometry) Within(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
 	val := C.OGR_G_Within(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if this geometry contains the other
 func (geom Geometry) Contains(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
 	val := C.OGR_G_Contains(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if this geometry overlaps the other
 func (geom Geometry) Overlaps(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
 	val := C.OGR_G_Overlaps(geom.cval, other.cval)
 	return val != 0
 }
 
 // Compute boundary for the geometry
 func (geom Geometry) Boundary() Geometry {
+	defer runtime.KeepAlive(geom)
 	newGeom := C.OGR_G_Boundary(geom.cval)
-	return Geometry{newGeom}
+	return ownGeometry(newGeom)
 }
 
 // Compute convex hull for the geometry
 func (geom Geometry) ConvexHull() Geometry {
+	defer runtime.KeepAlive(geom)
 	newGeom := C.OGR_G_ConvexHull(geom.cval)
-	return Geometry{newGeom}
+	return ownGeometry(newGeom)
 }
 
 // Compute buffer of the geometry
 func (geom Geometry) Buffer(distance float64, segments int) Geometry {
+	defer runtime.KeepAlive(geom)
 	newGeom := C.OGR_G_Buffer(geom.cval, C.double(distance), C.int(segments))
-	return Geometry{newGeom}
+	return ownGeometry(newGeom)
 }
 
 // Compute intersection of this geometry with the other
 func (geom Geometry) Intersection(other Geometry) Geometry {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
 	newGeom := C.OGR_G_Intersection(geom.cval, other.cval)
-	return Geometry{newGeom}
+	return ownGeometry(newGeom)
 }
 
 // Compute union of this geometry with the other
 func (geom Geometry) Union(other Geometry) Geometry {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
 	newGeom := C.OGR_G_Union(geom.cval, other.cval)
-	return Geometry{newGeom}
+	return ownGeometry(newGeom)
 }
 
 // Unimplemented: UnionCascaded
@@ -554,41 +650,50 @@ func (geom Geometry) Union(other Geometry) Geometry {
 // Return a point guaranteed to lie on the surface
 // func (geom Geometry) PointOnSurface() Geometry {
 //	newGeom := C.OGR_G_PointOnSurface(geom.cval)
-//	return Geometry{newGeom}
+//	return ownGeometry(newGeom)
 // }
 
 // Compute difference between this geometry and the other
 func (geom Geometry) Difference(other Geometry) Geometry {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
 	newGeom := C.OGR_G_Difference(geom.cval, other.cval)
-	return Geometry{newGeom}
+	return ownGeometry(newGeom)
 }
 
 // Compute symmetric difference between this geometry and the other
 func (geom Geometry) SymmetricDifference(other Geometry) Geometry {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
 	newGeom := C.OGR_G_SymDifference(geom.cval, other.cval)
-	return Geometry{newGeom}
+	return ownGeometry(newGeom)
 }
 
 // Compute distance between thie geometry and the other
 func (geom Geometry) Distance(other Geometry) float64 {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
 	dist := C.OGR_G_Distance(geom.cval, other.cval)
 	return float64(dist)
 }
 
 // Compute length of geometry
 func (geom Geometry) Length() float64 {
+	defer runtime.KeepAlive(geom)
 	length := C.OGR_G_Length(geom.cval)
 	return float64(length)
 }
 
 // Compute area of geometry
 func (geom Geometry) Area() float64 {
+	defer runtime.KeepAlive(geom)
 	area := C.OGR_G_Area(geom.cval)
 	return float64(area)
 }
 
 // Compute centroid of geometry
 func (geom Geometry) Centroid() Geometry {
+	defer runtime.KeepAlive(geom)
 	var centroid Geometry
 	C.OGR_G_Centroid(geom.cval, centroid.cval)
 	return centroid
@@ -596,41 +701,48 @@ func (geom Geometry) Centroid() Geometry {
 
 // Clear the geometry to its uninitialized state
 func (geom Geometry) Empty() {
+	defer runtime.KeepAlive(geom)
 	C.OGR_G_Empty(geom.cval)
 }
 
 // Test if the geometry is empty
 func (geom Geometry) IsEmpty() bool {
+	defer runtime.KeepAlive(geom)
 	val := C.OGR_G_IsEmpty(geom.cval)
 	return val != 0
 }
 
 // Test if the geometry is valid
 func (geom Geometry) IsValid() bool {
+	defer runtime.KeepAlive(geom)
 	val := C.OGR_G_IsValid(geom.cval)
 	return val != 0
 }
 
 // Test if the geometry is simple
 func (geom Geometry) IsSimple() bool {
+	defer runtime.KeepAlive(geom)
 	val := C.OGR_G_IsSimple(geom.cval)
 	return val != 0
 }
 
 // Test if the geometry is a ring
 func (geom Geometry) IsRing() bool {
+	defer runtime.KeepAlive(geom)
 	val := C.OGR_G_IsRing(geom.cval)
 	return val != 0
 }
 
 // Polygonize a set of sparse edges
 func (geom Geometry) Polygonize() Geometry {
+	defer runtime.KeepAlive(geom)
 	newGeom := C.OGR_G_Polygonize(geom.cval)
-	return Geometry{newGeom}
+	return ownGeometry(newGeom)
 }
 
 // Fetch number of points in the geometry
 func (geom Geometry) PointCount() int {
+	defer runtime.KeepAlive(geom)
 	count := C.OGR_G_GetPointCount(geom.cval)
 	return int(count)
 }
@@ -639,24 +751,28 @@ func (geom Geometry) PointCount() int {
 
 // Fetch the X coordinate of a point in the geometry
 func (geom Geometry) X(index int) float64 {
+	defer runtime.KeepAlive(geom)
 	x := C.OGR_G_GetX(geom.cval, C.int(index))
 	return float64(x)
 }
 
 // Fetch the Y coordinate of a point in the geometry
 func (geom Geometry) Y(index int) float64 {
+	defer runtime.KeepAlive(geom)
 	y := C.OGR_G_GetY(geom.cval, C.int(index))
 	return float64(y)
 }
 
 // Fetch the Z coordinate of a point in the geometry
 func (geom Geometry) Z(index int) float64 {
+	defer runtime.KeepAlive(geom)
 	z := C.OGR_G_GetZ(geom.cval, C.int(index))
 	return float64(z)
 }
 
 // Fetch the coordinates of a point in the geometry
 func (geom Geometry) Point(index int) (x, y, z float64) {
+	defer runtime.KeepAlive(geom)
 	C.OGR_G_GetPoint(
 		geom.cval,
 		C.int(index),
@@ -668,6 +784,7 @@ func (geom Geometry) Point(index int) (x, y, z float64) {
 
 // Set the coordinates of a point in the geometry
 func (geom Geometry) SetPoint(index int, x, y, z float64) {
+	defer runtime.KeepAlive(geom)
 	C.OGR_G_SetPoint(
 		geom.cval,
 		C.int(index),
@@ -678,60 +795,76 @@ func (geom Geometry) SetPoint(index int, x, y, z float64) {
 
 // Set the coordinates of a point in the geometry, ignoring the 3rd dimension
 func (geom Geometry) SetPoint2D(index int, x, y float64) {
+	defer runtime.KeepAlive(geom)
 	C.OGR_G_SetPoint_2D(geom.cval, C.int(index), C.double(x), C.double(y))
 }
 
 // Add a new point to the geometry (line string or polygon only)
 func (geom Geometry) AddPoint(x, y, z float64) {
+	defer runtime.KeepAlive(geom)
 	C.OGR_G_AddPoint(geom.cval, C.double(x), C.double(y), C.double(z))
 }
 
 // Add a new point to the geometry (line string or polygon only), ignoring the 3rd dimension
 func (geom Geometry) AddPoint2D(x, y float64) {
+	defer runtime.KeepAlive(geom)
 	C.OGR_G_AddPoint_2D(geom.cval, C.double(x), C.double(y))
 }
 
 // Fetch the number of elements in the geometry, or number of geometries in the container
 func (geom Geometry) GeometryCount() int {
+	defer runtime.KeepAlive(geom)
 	count := C.OGR_G_GetGeometryCount(geom.cval)
 	return int(count)
 }
 
 // Fetch geometry from a geometry container
 func (geom Geometry) Geometry(index int) Geometry {
+	defer runtime.KeepAlive(geom)
 	newGeom := C.OGR_G_GetGeometryRef(geom.cval, C.int(index))
-	return Geometry{newGeom}
+	return Geometry{newGeom, borrow(geom.owner)}
 }
 
 // Add a geometry to a geometry container
 func (geom Geometry) AddGeometry(other Geometry) error {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	defer captureErrors()()
 	err := C.OGR_G_AddGeometry(geom.cval, other.cval)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 	return nil
 }
 
 // Add a geometry to a geometry container and assign ownership to that container
 func (geom Geometry) AddGeometryDirectly(other Geometry) error {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	defer captureErrors()()
 	err := C.OGR_G_AddGeometryDirectly(geom.cval, other.cval)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
+	other.owner.disown()
 	return nil
 }
 
 // Remove a geometry from the geometry container
 func (geom Geometry) RemoveGeometry(index int, delete bool) error {
+	defer runtime.KeepAlive(geom)
+	defer captureErrors()()
 	err := C.OGR_G_RemoveGeometry(geom.cval, C.int(index), BoolToCInt(delete))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 	return nil
 }
 
 // Build a polygon / ring from a set of lines
 func (geom Geometry) BuildPolygonFromEdges(autoClose bool, tolerance float64) (Geometry, error) {
+	defer runtime.KeepAlive(geom)
+	defer captureErrors()()
 	var err C.OGRErr
 	newGeom := C.OGRBuildPolygonFromEdges(
 		geom.cval,
@@ -741,9 +874,9 @@ func (geom Geometry) BuildPolygonFromEdges(autoClose bool, tolerance float64) (G
 		&err,
 	)
 	if err != 0 {
-		return Geometry{}, error(err)
+		return Geometry{}, ogrError(err)
 	}
-	return Geometry{newGeom}, nil
+	return ownGeometry(newGeom), nil
 }
 
 /* -------------------------------------------------------------------- */
@@ -775,7 +908,8 @@ const (
 )
 
 type FieldDefinition struct {
-	cval C.OGRFieldDefnH
+	cval  C.OGRFieldDefnH
+	owner *owner
 }
 
 type Field struct {
@@ -787,22 +921,25 @@ func CreateFieldDefinition(name string, fieldType FieldType) FieldDefinition {
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	fieldDef := C.OGR_Fld_Create(cName, C.OGRFieldType(fieldType))
-	return FieldDefinition{fieldDef}
+	return FieldDefinition{fieldDef, own(func() { C.OGR_Fld_Destroy(fieldDef) })}
 }
 
-// Destroy the field definition
+// Destroy the field definition.  Definitions owned by a feature definition
+// are left alone.
 func (fd FieldDefinition) Destroy() {
-	C.OGR_Fld_Destroy(fd.cval)
+	fd.owner.release()
 }
 
 // Fetch the name of the field
 func (fd FieldDefinition) Name() string {
+	defer runtime.KeepAlive(fd)
 	name := C.OGR_Fld_GetNameRef(fd.cval)
 	return C.GoString(name)
 }
 
 // Set the name of the field
 func (fd FieldDefinition) SetName(name string) {
+	defer runtime.KeepAlive(fd)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	C.OGR_Fld_SetName(fd.cval, cName)
@@ -810,45 +947,53 @@ func (fd FieldDefinition) SetName(name string) {
 
 // Fetch the type of this field
 func (fd FieldDefinition) Type() FieldType {
+	defer runtime.KeepAlive(fd)
 	fType := C.OGR_Fld_GetType(fd.cval)
 	return FieldType(fType)
 }
 
 // Set the type of this field
 func (fd FieldDefinition) SetType(fType FieldType) {
+	defer runtime.KeepAlive(fd)
 	C.OGR_Fld_SetType(fd.cval, C.OGRFieldType(fType))
 }
 
 // Fetch the justification for this field
 func (fd FieldDefinition) Justification() Justification {
+	defer runtime.KeepAlive(fd)
 	justify := C.OGR_Fld_GetJustify(fd.cval)
 	return Justification(justify)
 }
 
 // Set the justification for this field
 func (fd FieldDefinition) SetJustification(justify Justification) {
+	defer runtime.KeepAlive(fd)
 	C.OGR_Fld_SetJustify(fd.cval, C.OGRJustification(justify))
 }
 
 // Fetch the formatting width for this field
 func (fd FieldDefinition) Width() int {
+	defer runtime.KeepAlive(fd)
 	width := C.OGR_Fld_GetWidth(fd.cval)
 	return int(width)
 }
 
 // Set the formatting width for this field
 func (fd FieldDefinition) SetWidth(width int) {
+	defer runtime.KeepAlive(fd)
 	C.OGR_Fld_SetWidth(fd.cval, C.int(width))
 }
 
 // Fetch the precision for this field
 func (fd FieldDefinition) Precision() int {
+	defer runtime.KeepAlive(fd)
 	precision := C.OGR_Fld_GetPrecision(fd.cval)
 	return int(precision)
 }
 
 // Set the precision for this field
 func (fd FieldDefinition) SetPrecision(precision int) {
+	defer runtime.KeepAlive(fd)
 	C.OGR_Fld_SetPrecision(fd.cval, C.int(precision))
 }
 
@@ -859,6 +1004,7 @@ func (fd FieldDefinition) Set(
 	width, precision int,
 	justify Justification,
 ) {
+	defer runtime.KeepAlive(fd)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 
@@ -874,12 +1020,14 @@ func (fd FieldDefinition) Set(
 
 // Fetch whether this field should be ignored when fetching features
 func (fd FieldDefinition) IsIgnored() bool {
+	defer runtime.KeepAlive(fd)
 	ignore := C.OGR_Fld_IsIgnored(fd.cval)
 	return ignore != 0
 }
 
 // Set whether this field should be ignored when fetching features
 func (fd FieldDefinition) SetIgnored(ignore bool) {
+	defer runtime.KeepAlive(fd)
 	C.OGR_Fld_SetIgnored(fd.cval, BoolToCInt(ignore))
 }
 
@@ -894,7 +1042,8 @@ func (ft FieldType) Name() string {
 /* -------------------------------------------------------------------- */
 
 type FeatureDefinition struct {
-	cval C.OGRFeatureDefnH
+	cval  C.OGRFeatureDefnH
+	owner *owner
 }
 
 // Create a new feature definition object
@@ -902,39 +1051,47 @@ func CreateFeatureDefinition(name string) FeatureDefinition {
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	fd := C.OGR_FD_Create(cName)
-	return FeatureDefinition{fd}
+	C.OGR_FD_Reference(fd)
+	return FeatureDefinition{fd, own(func() { C.OGR_FD_Release(fd) })}
 }
 
-// Destroy a feature definition object
+// Destroy a feature definition object.  The object is only deleted once
+// the features created from it are destroyed too, and definitions owned by
+// a layer or a feature are left alone.
 func (fd FeatureDefinition) Destroy() {
-	C.OGR_FD_Destroy(fd.cval)
+	fd.owner.release()
 }
 
-// Drop a reference, and delete object if no references remain
+// Drop the reference held by this handle, and delete object if no
+// references remain
 func (fd FeatureDefinition) Release() {
-	C.OGR_FD_Release(fd.cval)
+	fd.owner.release()
 }
 
 // Fetch the name of this feature definition
 func (fd FeatureDefinition) Name() string {
+	defer runtime.KeepAlive(fd)
 	name := C.OGR_FD_GetName(fd.cval)
 	return C.GoString(name)
 }
 
 // Fetch the number of fields in the feature definition
 func (fd FeatureDefinition) FieldCount() int {
+	defer runtime.KeepAlive(fd)
 	count := C.OGR_FD_GetFieldCount(fd.cval)
 	return int(count)
 }
 
 // Fetch the definition of the indicated field
 func (fd FeatureDefinition) FieldDefinition(index int) FieldDefinition {
+	defer runtime.KeepAlive(fd)
 	fieldDefn := C.OGR_FD_GetFieldDefn(fd.cval, C.int(index))
-	return FieldDefinition{fieldDefn}
+	return FieldDefinition{fieldDefn, borrow(fd.owner)}
 }
 
 // Fetch the index of the named field
 func (fd FeatureDefinition) FieldIndex(name string) int {
+	defer runtime.KeepAlive(fd)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	index := C.OGR_FD_GetFieldIndex(fd.cval, cName)
@@ -943,62 +1100,75 @@ func (fd FeatureDefinition) FieldIndex(name string) int {
 
 // Add a new field definition to this feature definition
 func (fd FeatureDefinition) AddFieldDefinition(fieldDefn FieldDefinition) {
+	defer runtime.KeepAlive(fd)
+	defer runtime.KeepAlive(fieldDefn)
 	C.OGR_FD_AddFieldDefn(fd.cval, fieldDefn.cval)
 }
 
 // Delete a field definition from this feature definition
 func (fd FeatureDefinition) DeleteFieldDefinition(index int) error {
+	defer runtime.KeepAlive(fd)
+	defer captureErrors()()
 	err := C.OGR_FD_DeleteFieldDefn(fd.cval, C.int(index))
-	return error(err)
+	return ogrError(err)
 }
 
 // Fetch the geometry base type of this feature definition
 func (fd FeatureDefinition) GeometryType() GeometryType {
+	defer runtime.KeepAlive(fd)
 	gt := C.OGR_FD_GetGeomType(fd.cval)
 	return GeometryType(gt)
 }
 
 // Set the geometry base type for this feature definition
 func (fd FeatureDefinition) SetGeometryType(geomType GeometryType) {
+	defer runtime.KeepAlive(fd)
 	C.OGR_FD_SetGeomType(fd.cval, C.OGRwkbGeometryType(geomType))
 }
 
 // Fetch if the geometry can be ignored when fetching features
 func (fd FeatureDefinition) IsGeometryIgnored() bool {
+	defer runtime.KeepAlive(fd)
 	isIgnored := C.OGR_FD_IsGeometryIgnored(fd.cval)
 	return isIgnored != 0
 }
 
 // Set whether the geometry can be ignored when fetching features
 func (fd FeatureDefinition) SetGeometryIgnored(val bool) {
+	defer runtime.KeepAlive(fd)
 	C.OGR_FD_SetGeometryIgnored(fd.cval, BoolToCInt(val))
 }
 
 // Fetch if the style can be ignored when fetching features
 func (fd FeatureDefinition) IsStyleIgnored() bool {
+	defer runtime.KeepAlive(fd)
 	isIgnored := C.OGR_FD_IsStyleIgnored(fd.cval)
 	return isIgnored != 0
 }
 
 // Set whether the style can be ignored when fetching features
 func (fd FeatureDefinition) SetStyleIgnored(val bool) {
+	defer runtime.KeepAlive(fd)
 	C.OGR_FD_SetStyleIgnored(fd.cval, BoolToCInt(val))
 }
 
 // Increment the reference count by one
 func (fd FeatureDefinition) Reference() int {
+	defer runtime.KeepAlive(fd)
 	count := C.OGR_FD_Reference(fd.cval)
 	return int(count)
 }
 
 // Decrement the reference count by one
 func (fd FeatureDefinition) Dereference() int {
+	defer runtime.KeepAlive(fd)
 	count := C.OGR_FD_Dereference(fd.cval)
 	return int(count)
 }
 
 // Fetch the current reference count
 func (fd FeatureDefinition) ReferenceCount() int {
+	defer runtime.KeepAlive(fd)
 	count := C.OGR_FD_GetReferenceCount(fd.cval)
 	return int(count)
 }
@@ -1008,82 +1178,111 @@ func (fd FeatureDefinition) ReferenceCount() int {
 /* -------------------------------------------------------------------- */
 
 type Feature struct {
-	cval C.OGRFeatureH
+	cval  C.OGRFeatureH
+	owner *owner
+}
+
+// Wrap a feature handle owned by the caller
+func ownFeature(cval C.OGRFeatureH) Feature {
+	if cval == nil {
+		return Feature{}
+	}
+	return Feature{cval, own(func() { C.OGR_F_Destroy(cval) })}
 }
 
 // Create a feature from this feature definition
 func (fd FeatureDefinition) Create() Feature {
+	defer runtime.KeepAlive(fd)
 	feature := C.OGR_F_Create(fd.cval)
-	return Feature{feature}
+	return ownFeature(feature)
 }
 
-// Destroy this feature
+// Destroy this feature.  Destroying a feature twice does nothing.
 func (feature Feature) Destroy() {
-	C.OGR_F_Destroy(feature.cval)
+	feature.owner.release()
 }
 
 // Fetch feature definition
 func (feature Feature) Definition() FeatureDefinition {
+	defer runtime.KeepAlive(feature)
 	fd := C.OGR_F_GetDefnRef(feature.cval)
-	return FeatureDefinition{fd}
+	return FeatureDefinition{fd, borrow(feature.owner)}
 }
 
 // Set feature geometry
 func (feature Feature) SetGeometry(geom Geometry) error {
+	defer runtime.KeepAlive(feature)
+	defer runtime.KeepAlive(geom)
+	defer captureErrors()()
 	err := C.OGR_F_SetGeometry(feature.cval, geom.cval)
-	return error(err)
+	return ogrError(err)
 }
 
 // Set feature geometry, passing ownership to the feature
 func (feature Feature) SetGeometryDirectly(geom Geometry) error {
+	defer runtime.KeepAlive(feature)
+	defer runtime.KeepAlive(geom)
+	defer captureErrors()()
 	err := C.OGR_F_SetGeometryDirectly(feature.cval, geom.cval)
-	return error(err)
+	if err != 0 {
+		return ogrError(err)
+	}
+	geom.owner.disown()
+	return nil
 }
 
 // Fetch geometry of this feature, returning ok == false if feature has no geometry (possible in KML)
 func (feature Feature) Geometry() (g Geometry, ok bool) {
+	defer runtime.KeepAlive(feature)
 	geom := C.OGR_F_GetGeometryRef(feature.cval)
 	if geom == nil {
 		return Geometry{}, false
 	}
-	return Geometry{geom}, true
+	return Geometry{geom, borrow(feature.owner)}, true
 }
 
 // Fetch geometry of this feature and assume ownership, returning ok == false if feature has no geometry (possible in KML)
 func (feature Feature) StealGeometry() (g Geometry, ok bool) {
+	defer runtime.KeepAlive(feature)
 	geom := C.OGR_F_StealGeometry(feature.cval)
 	if geom == nil {
 		return Geometry{}, false
 	}
-	return Geometry{geom}, true
+	return ownGeometry(geom), true
 }
 
 // Duplicate feature
 func (feature Feature) Clone() Feature {
+	defer runtime.KeepAlive(feature)
 	newFeature := C.OGR_F_Clone(feature.cval)
-	return Feature{newFeature}
+	return ownFeature(newFeature)
 }
 
 // Test if two features are the same
 func (f1 Feature) Equal(f2 Feature) bool {
+	defer runtime.KeepAlive(f1)
+	defer runtime.KeepAlive(f2)
 	equal := C.OGR_F_Equal(f1.cval, f2.cval)
 	return equal != 0
 }
 
 // Fetch number of fields on this feature
 func (feature Feature) FieldCount() int {
+	defer runtime.KeepAlive(feature)
 	count := C.OGR_F_GetFieldCount(feature.cval)
 	return int(count)
 }
 
 // Fetch definition for the indicated field
 func (feature Feature) FieldDefinition(index int) FieldDefinition {
+	defer runtime.KeepAlive(feature)
 	defn := C.OGR_F_GetFieldDefnRef(feature.cval, C.int(index))
-	return FieldDefinition{defn}
+	return FieldDefinition{defn, borrow(feature.owner)}
 }
 
 // Fetch the field index for the given field name
 func (feature Feature) FieldIndex(name string) int {
+	defer runtime.KeepAlive(feature)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	index := C.OGR_F_GetFieldIndex(feature.cval, cName)
@@ -1092,41 +1291,48 @@ func (feature Feature) FieldIndex(name string) int {
 
 // Return if a field has ever been assigned a value
 func (feature Feature) IsFieldSet(index int) bool {
+	defer runtime.KeepAlive(feature)
 	set := C.OGR_F_IsFieldSet(feature.cval, C.int(index))
 	return set != 0
 }
 
 // Clear a field and mark it as unset
 func (feature Feature) UnsetField(index int) {
+	defer runtime.KeepAlive(feature)
 	C.OGR_F_UnsetField(feature.cval, C.int(index))
 }
 
 // Fetch a reference to the internal field value
 func (feature Feature) RawField(index int) Field {
+	defer runtime.KeepAlive(feature)
 	field := C.OGR_F_GetRawFieldRef(feature.cval, C.int(index))
 	return Field{field}
 }
 
 // Fetch field value as integer
 func (feature Feature) FieldAsInteger(index int) int {
+	defer runtime.KeepAlive(feature)
 	val := C.OGR_F_GetFieldAsInteger(feature.cval, C.int(index))
 	return int(val)
 }
 
 // Fetch field value as float64
 func (feature Feature) FieldAsFloat64(index int) float64 {
+	defer runtime.KeepAlive(feature)
 	val := C.OGR_F_GetFieldAsDouble(feature.cval, C.int(index))
 	return float64(val)
 }
 
 // Fetch field value as string
 func (feature Feature) FieldAsString(index int) string {
+	defer runtime.KeepAlive(feature)
 	val := C.OGR_F_GetFieldAsString(feature.cval, C.int(index))
 	return C.GoString(val)
 }
 
 // Fetch field as list of integers
 func (feature Feature) FieldAsIntegerList(index int) []int {
+	defer runtime.KeepAlive(feature)
 	var count int
 	cArray := C.OGR_F_GetFieldAsIntegerList(feature.cval, C.int(index), (*C.int)(unsafe.Pointer(&count)))
 	var goSlice []int
@@ -1139,6 +1345,7 @@ func (feature Feature) FieldAsIntegerList(index int) []int {
 
 // Fetch field as list of float64
 func (feature Feature) FieldAsFloat64List(index int) []float64 {
+	defer runtime.KeepAlive(feature)
 	var count int
 	cArray := C.OGR_F_GetFieldAsDoubleList(feature.cval, C.int(index), (*C.int)(unsafe.Pointer(&count)))
 	var goSlice []float64
@@ -1151,24 +1358,17 @@ func (feature Feature) FieldAsFloat64List(index int) []float64 {
 
 // Fetch field as list of strings
 func (feature Feature) FieldAsStringList(index int) []string {
+	defer runtime.KeepAlive(feature)
 	p := C.OGR_F_GetFieldAsStringList(feature.cval, C.int(index))
 
-	var strings []string
-	q := uintptr(unsafe.Pointer(p))
-	for {
-		p = (**C.char)(unsafe.Pointer(q))
-		if *p == nil {
-			break
-		}
-		strings = append(strings, C.GoString(*p))
-		q += unsafe.Sizeof(q)
-	}
+	strings := goStringList(p)
 
 	return strings
 }
 
 // Fetch field as binary data
 func (feature Feature) FieldAsBinary(index int) []uint8 {
+	defer runtime.KeepAlive(feature)
 	var count int
 	cArray := C.OGR_F_GetFieldAsBinary(feature.cval, C.int(index), (*C.int)(unsafe.Pointer(&count)))
 	var goSlice []uint8
@@ -1181,6 +1381,7 @@ func (feature Feature) FieldAsBinary(index int) []uint8 {
 
 // Fetch field as date and time
 func (feature Feature) FieldAsDateTime(index int) (time.Time, bool) {
+	defer runtime.KeepAlive(feature)
 	var year, month, day, hour, minute, second, tzFlag int
 	success := C.OGR_F_GetFieldAsDateTime(
 		feature.cval,
@@ -1199,16 +1400,19 @@ func (feature Feature) FieldAsDateTime(index int) (time.Time, bool) {
 
 // Set field to integer value
 func (feature Feature) SetFieldInteger(index, value int) {
+	defer runtime.KeepAlive(feature)
 	C.OGR_F_SetFieldInteger(feature.cval, C.int(index), C.int(value))
 }
 
 // Set field to float64 value
 func (feature Feature) SetFieldFloat64(index int, value float64) {
+	defer runtime.KeepAlive(feature)
 	C.OGR_F_SetFieldDouble(feature.cval, C.int(index), C.double(value))
 }
 
 // Set field to string value
 func (feature Feature) SetFieldString(index int, value string) {
+	defer runtime.KeepAlive(feature)
 	cVal := C.CString(value)
 	defer C.free(unsafe.Pointer(cVal))
 	C.OGR_F_SetFieldString(feature.cval, C.int(index), cVal)
@@ -1216,6 +1420,7 @@ func (feature Feature) SetFieldString(index int, value string) {
 
 // Set field to list of integers
 func (feature Feature) SetFieldIntegerList(index int, value []int) {
+	defer runtime.KeepAlive(feature)
 	C.OGR_F_SetFieldIntegerList(
 		feature.cval,
 		C.int(index),
@@ -1226,6 +1431,7 @@ func (feature Feature) SetFieldIntegerList(index int, value []int) {
 
 // Set field to list of float64
 func (feature Feature) SetFieldFloat64List(index int, value []float64) {
+	defer runtime.KeepAlive(feature)
 	C.OGR_F_SetFieldDoubleList(
 		feature.cval,
 		C.int(index),
@@ -1236,6 +1442,7 @@ func (feature Feature) SetFieldFloat64List(index int, value []float64) {
 
 // Set field to list of strings
 func (feature Feature) SetFieldStringList(index int, value []string) {
+	defer runtime.KeepAlive(feature)
 	length := len(value)
 	cValue := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -1253,11 +1460,13 @@ func (feature Feature) SetFieldStringList(index int, value []string) {
 
 // Set field from the raw field pointer
 func (feature Feature) SetFieldRaw(index int, field Field) {
+	defer runtime.KeepAlive(feature)
 	C.OGR_F_SetFieldRaw(feature.cval, C.int(index), field.cval)
 }
 
 // Set field as binary data
 func (feature Feature) SetFieldBinary(index int, value []uint8) {
+	defer runtime.KeepAlive(feature)
 	C.OGR_F_SetFieldBinary(
 		feature.cval,
 		C.int(index),
@@ -1268,6 +1477,7 @@ func (feature Feature) SetFieldBinary(index int, value []uint8) {
 
 // Set field as date / time
 func (feature Feature) SetFieldDateTime(index int, dt time.Time) {
+	defer runtime.KeepAlive(feature)
 	C.OGR_F_SetFieldDateTime(
 		feature.cval,
 		C.int(index),
@@ -1283,43 +1493,54 @@ func (feature Feature) SetFieldDateTime(index int, dt time.Time) {
 
 // Fetch feature indentifier
 func (feature Feature) FID() int {
+	defer runtime.KeepAlive(feature)
 	fid := C.OGR_F_GetFID(feature.cval)
 	return int(fid)
 }
 
 // Set feature identifier
 func (feature Feature) SetFID(fid int) error {
-	err := C.OGR_F_SetFID(feature.cval, C.long(fid))
-	return error(err)
+	defer runtime.KeepAlive(feature)
+	defer captureErrors()()
+	err := C.OGR_F_SetFID(feature.cval, C.goGDALFID(fid))
+	return ogrError(err)
 }
 
 // Unimplemented: DumpReadable
 
 // Set one feature from another
 func (this Feature) SetFrom(other Feature, forgiving int) error {
+	defer runtime.KeepAlive(this)
+	defer runtime.KeepAlive(other)
+	defer captureErrors()()
 	err := C.OGR_F_SetFrom(this.cval, other.cval, C.int(forgiving))
-	return error(err)
+	return ogrError(err)
 }
 
 // Set one feature from another, using field map
 func (this Feature) SetFromWithMap(other Feature, forgiving int, fieldMap []int) error {
+	defer runtime.KeepAlive(this)
+	defer runtime.KeepAlive(other)
+	defer captureErrors()()
 	err := C.OGR_F_SetFromWithMap(
 		this.cval,
 		other.cval,
 		C.int(forgiving),
 		(*C.int)(unsafe.Pointer(&fieldMap[0])),
 	)
-	return error(err)
+	return ogrError(err)
 }
 
 // Fetch style string for this feature
 func (feature Feature) StyleString() string {
+	defer runtime.KeepAlive(feature)
 	style := C.OGR_F_GetStyleString(feature.cval)
 	return C.GoString(style)
 }
 
 // Set style string for this feature
 func (feature Feature) SetStyleString(style string) {
+	defer runtime.KeepAlive(feature)
 	cStyle := C.CString(style)
 	C.OGR_F_SetStyleStringDirectly(feature.cval, cStyle)
 }
@@ -1329,34 +1550,41 @@ func (feature Feature) SetStyleString(style string) {
 /* -------------------------------------------------------------------- */
 
 type Layer struct {
-	cval C.OGRLayerH
+	cval   C.OGRLayerH
+	parent *owner
 }
 
 // Return the layer name
 func (layer Layer) Name() string {
+	defer runtime.KeepAlive(layer)
 	name := C.OGR_L_GetName(layer.cval)
 	return C.GoString(name)
 }
 
 // Return the layer geometry type
 func (layer Layer) Type() GeometryType {
+	defer runtime.KeepAlive(layer)
 	gt := C.OGR_L_GetGeomType(layer.cval)
 	return GeometryType(gt)
 }
 
 // Return the current spatial filter for this layer
 func (layer Layer) SpatialFilter() Geometry {
+	defer runtime.KeepAlive(layer)
 	geom := C.OGR_L_GetSpatialFilter(layer.cval)
-	return Geometry{geom}
+	return Geometry{geom, borrow(layer.parent)}
 }
 
 // Set a new spatial filter for this layer
 func (layer Layer) SetSpatialFilter(filter Geometry) {
+	defer runtime.KeepAlive(layer)
+	defer runtime.KeepAlive(filter)
 	C.OGR_L_SetSpatialFilter(layer.cval, filter.cval)
 }
 
 // Set a new rectangular spatial filter for this layer
 func (layer Layer) SetSpatialFilterRect(minX, minY, maxX, maxY float64) {
+	defer runtime.KeepAlive(layer)
 	C.OGR_L_SetSpatialFilterRect(
 		layer.cval,
 		C.double(minX), C.double(minY), C.double(maxX), C.double(maxY),
@@ -1365,83 +1593,104 @@ func (layer Layer) SetSpatialFilterRect(minX, minY, maxX, maxY float64) {
 
 // Set a new attribute query filter
 func (layer Layer) SetAttributeFilter(filter string) error {
+	defer runtime.KeepAlive(layer)
 	cFilter := C.CString(filter)
 	defer C.free(unsafe.Pointer(cFilter))
+	defer captureErrors()()
 	err := C.OGR_L_SetAttributeFilter(layer.cval, cFilter)
-	return error(err)
+	return ogrError(err)
 }
 
 // Reset reading to start on the first featre
 func (layer Layer) ResetReading() {
+	defer runtime.KeepAlive(layer)
 	C.OGR_L_ResetReading(layer.cval)
 }
 
 // Fetch the next available feature from this layer; call Destroy() on it when done; returns ok =
 // false if there are no more features in the layer.
 func (layer Layer) NextFeature() (f Feature, ok bool) {
+	defer runtime.KeepAlive(layer)
 	feature := C.OGR_L_GetNextFeature(layer.cval)
 	if feature == nil {
 		return Feature{}, false
 	}
-	return Feature{feature}, true
+	return ownFeature(feature), true
 }
 
 // Move read cursor to the provided index
 func (layer Layer) SetNextByIndex(index int) error {
-	err := C.OGR_L_SetNextByIndex(layer.cval, C.long(index))
-	return error(err)
+	defer runtime.KeepAlive(layer)
+	defer captureErrors()()
+	err := C.OGR_L_SetNextByIndex(layer.cval, C.goGDALFID(index))
+	return ogrError(err)
 }
 
 // Fetch a feature by its index
 func (layer Layer) Feature(index int) Feature {
-	feature := C.OGR_L_GetFeature(layer.cval, C.long(index))
-	return Feature{feature}
+	defer runtime.KeepAlive(layer)
+	feature := C.OGR_L_GetFeature(layer.cval, C.goGDALFID(index))
+	return ownFeature(feature)
 }
 
 // Rewrite the provided feature
 func (layer Layer) SetFeature(feature Feature) error {
+	defer runtime.KeepAlive(layer)
+	defer runtime.KeepAlive(feature)
+	defer captureErrors()()
 	err := C.OGR_L_SetFeature(layer.cval, feature.cval)
-	return error(err)
+	return ogrError(err)
 }
 
 // Create and write a new feature within a layer
 func (layer Layer) Create(feature Feature) error {
+	defer runtime.KeepAlive(layer)
+	defer runtime.KeepAlive(feature)
+	defer captureErrors()()
 	err := C.OGR_L_CreateFeature(layer.cval, feature.cval)
-	return error(err)
+	return ogrError(err)
 }
 
 // Delete indicated feature from layer
 func (layer Layer) Delete(index int) error {
-	err := C.OGR_L_DeleteFeature(layer.cval, C.long(index))
-	return error(err)
+	defer runtime.KeepAlive(layer)
+	defer captureErrors()()
+	err := C.OGR_L_DeleteFeature(layer.cval, C.goGDALFID(index))
+	return ogrError(err)
 }
 
 // Fetch the schema information for this layer
 func (layer Layer) Definition() FeatureDefinition {
+	defer runtime.KeepAlive(layer)
 	defn := C.OGR_L_GetLayerDefn(layer.cval)
-	return FeatureDefinition{defn}
+	return FeatureDefinition{defn, borrow(layer.parent)}
 }
 
 // Fetch the spatial reference system for this layer
 func (layer Layer) SpatialReference() SpatialReference {
+	defer runtime.KeepAlive(layer)
 	sr := C.OGR_L_GetSpatialRef(layer.cval)
-	return SpatialReference{sr}
+	return SpatialReference{sr, borrow(layer.parent)}
 }
 
 // Fetch the feature count for this layer
 func (layer Layer) FeatureCount(force bool) (count int, ok bool) {
+	defer runtime.KeepAlive(layer)
 	count = int(C.OGR_L_GetFeatureCount(layer.cval, BoolToCInt(force)))
 	return count, count != -1
 }
 
 // Fetch the extent of this layer
 func (layer Layer) Extent(force bool) (env Envelope, err error) {
-	err = error(C.OGR_L_GetExtent(layer.cval, &env.cval, BoolToCInt(force)))
+	defer runtime.KeepAlive(layer)
+	defer captureErrors()()
+	err = ogrError(C.OGR_L_GetExtent(layer.cval, &env.cval, BoolToCInt(force)))
 	return
 }
 
 // Test if this layer supports the named capability
 func (layer Layer) TestCapability(capability string) bool {
+	defer runtime.KeepAlive(layer)
 	cString := C.CString(capability)
 	defer C.free(unsafe.Pointer(cString))
 	val := C.OGR_L_TestCapability(layer.cval, cString)
@@ -1450,72 +1699,95 @@ func (layer Layer) TestCapability(capability string) bool {
 
 // Create a new field on a layer
 func (layer Layer) CreateField(fd FieldDefinition, approxOK bool) error {
+	defer runtime.KeepAlive(layer)
+	defer runtime.KeepAlive(fd)
+	defer captureErrors()()
 	err := C.OGR_L_CreateField(layer.cval, fd.cval, BoolToCInt(approxOK))
-	return error(err)
+	return ogrError(err)
 }
 
 // Delete a field from the layer
 func (layer Layer) DeleteField(index int) error {
+	defer runtime.KeepAlive(layer)
+	defer captureErrors()()
 	err := C.OGR_L_DeleteField(layer.cval, C.int(index))
-	return error(err)
+	return ogrError(err)
 }
 
 // Reorder all the fields of a layer
 func (layer Layer) ReorderFields(layerMap []int) error {
+	defer runtime.KeepAlive(layer)
+	defer captureErrors()()
 	err := C.OGR_L_ReorderFields(layer.cval, (*C.int)(unsafe.Pointer(&layerMap[0])))
-	return error(err)
+	return ogrError(err)
 }
 
 // Reorder an existing field of a layer
 func (layer Layer) ReorderField(oldIndex, newIndex int) error {
+	defer runtime.KeepAlive(layer)
+	defer captureErrors()()
 	err := C.OGR_L_ReorderField(layer.cval, C.int(oldIndex), C.int(newIndex))
-	return error(err)
+	return ogrError(err)
 }
 
 // Alter the definition of an existing field of a layer
 func (layer Layer) AlterFieldDefn(index int, newDefn FieldDefinition, flags int) error {
+	defer runtime.KeepAlive(layer)
+	defer runtime.KeepAlive(newDefn)
+	defer captureErrors()()
 	err := C.OGR_L_AlterFieldDefn(layer.cval, C.int(index), newDefn.cval, C.int(flags))
-	return error(err)
+	return ogrError(err)
 }
 
 // Begin a transation on data sources which support it
 func (layer Layer) StartTransaction() error {
+	defer runtime.KeepAlive(layer)
+	defer captureErrors()()
 	err := C.OGR_L_StartTransaction(layer.cval)
-	return error(err)
+	return ogrError(err)
 }
 
 // Commit a transaction on data sources which support it
 func (layer Layer) CommitTransaction() error {
+	defer runtime.KeepAlive(layer)
+	defer captureErrors()()
 	err := C.OGR_L_CommitTransaction(layer.cval)
-	return error(err)
+	return ogrError(err)
 }
 
 // Roll back the current transaction on data sources which support it
 func (layer Layer) RollbackTransaction() error {
+	defer runtime.KeepAlive(layer)
+	defer captureErrors()()
 	err := C.OGR_L_RollbackTransaction(layer.cval)
-	return error(err)
+	return ogrError(err)
 }
 
 // Flush pending changes to the layer
 func (layer Layer) Sync() error {
+	defer runtime.KeepAlive(layer)
+	defer captureErrors()()
 	err := C.OGR_L_SyncToDisk(layer.cval)
-	return error(err)
+	return ogrError(err)
 }
 
 // Fetch the name of the FID column
 func (layer Layer) FIDColumn() string {
+	defer runtime.KeepAlive(layer)
 	name := C.OGR_L_GetFIDColumn(layer.cval)
 	return C.GoString(name)
 }
 
 // Fetch the name of the geometry column
 func (layer Layer) GeometryColumn() string {
+	defer runtime.KeepAlive(layer)
 	name := C.OGR_L_GetGeometryColumn(layer.cval)
 	return C.GoString(name)
 }
 
 // Set which fields can be ignored when retrieving features from the layer
 func (layer Layer) SetIgnoredFields(names []string) error {
+	defer runtime.KeepAlive(layer)
 	length := len(names)
 	cNames := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -1524,8 +1796,9 @@ func (layer Layer) SetIgnoredFields(names []string) error {
 	}
 	cNames[length] = (*C.char)(unsafe.Pointer(nil))
 
+	defer captureErrors()()
 	err := C.OGR_L_SetIgnoredFields(layer.cval, (**C.char)(unsafe.Pointer(&cNames[0])))
-	return error(err)
+	return ogrError(err)
 }
 
 // Return the intersection of two layers
@@ -1561,18 +1834,28 @@ func (layer Layer) SetIgnoredFields(names []string) error {
 /* -------------------------------------------------------------------- */
 
 type DataSource struct {
-	cval C.OGRDataSourceH
+	cval  C.OGRDataSourceH
+	owner *owner
+}
+
+// Wrap a data source handle owned by the caller
+func ownDataSource(cval C.OGRDataSourceH) DataSource {
+	if cval == nil {
+		return DataSource{}
+	}
+	return DataSource{cval, own(func() { C.OGR_DS_Destroy(cval) })}
 }
 
 // Open a file / data source with one of the registered drivers; call Release() on it when done
 func OpenDataSource(name string, update int) (DataSource, error) {
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	ds := C.OGROpen(cName, C.int(update), nil)
 	if ds == nil {
-		return DataSource{}, errors.New("Failed to open " + name)
+		return DataSource{}, lastError(CPLE_OpenFailed, "Failed to open %s", name)
 	}
-	return DataSource{ds}, nil
+	return ownDataSource(ds), nil
 }
 
 // Open a shared file / data source with one of the registered drivers
@@ -1580,14 +1863,23 @@ func OpenSharedDataSource(name string, update int) DataSource {
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	ds := C.OGROpenShared(cName, C.int(update), nil)
-	return DataSource{ds}
+	if ds == nil {
+		return DataSource{}
+	}
+	return DataSource{ds, own(func() { C.OGRReleaseDataSource(ds) })}
 }
 
 // Drop a reference to this datasource and destroy if reference is zero
 func (ds DataSource) Release() error {
+	defer runtime.KeepAlive(ds)
+	if !ds.owner.owned() {
+		return nil
+	}
+	ds.owner.disown()
+	defer captureErrors()()
 	err := C.OGRReleaseDataSource(ds.cval)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 	return nil
 }
@@ -1601,48 +1893,56 @@ func OpenDataSourceCount() int {
 // Return the i'th datasource opened
 func OpenDataSourceByIndex(index int) DataSource {
 	ds := C.OGRGetOpenDS(C.int(index))
-	return DataSource{ds}
+	return DataSource{ds, nil}
 }
 
-// Closes datasource and releases resources
+// Closes datasource and releases resources.  Closing a data source more
+// than once, or one that was not opened by the caller, does nothing.
 func (ds DataSource) Destroy() {
-	C.OGR_DS_Destroy(ds.cval)
+	ds.owner.release()
 }
 
 // Fetch the name of the data source
 func (ds DataSource) Name() string {
+	defer runtime.KeepAlive(ds)
 	name := C.OGR_DS_GetName(ds.cval)
 	return C.GoString(name)
 }
 
 // Fetch the number of layers in this data source
 func (ds DataSource) LayerCount() int {
+	defer runtime.KeepAlive(ds)
 	count := C.OGR_DS_GetLayerCount(ds.cval)
 	return int(count)
 }
 
 // Fetch a layer of this data source by index
 func (ds DataSource) LayerByIndex(index int) Layer {
+	defer runtime.KeepAlive(ds)
 	layer := C.OGR_DS_GetLayer(ds.cval, C.int(index))
-	return Layer{layer}
+	return Layer{layer, ds.owner}
 }
 
 // Fetch a layer of this data source by name
 func (ds DataSource) LayerByName(name string) Layer {
+	defer runtime.KeepAlive(ds)
 	cString := C.CString(name)
 	defer C.free(unsafe.Pointer(cString))
 	layer := C.OGR_DS_GetLayerByName(ds.cval, cString)
-	return Layer{layer}
+	return Layer{layer, ds.owner}
 }
 
 // Delete the layer from the data source
 func (ds DataSource) Delete(index int) error {
+	defer runtime.KeepAlive(ds)
+	defer captureErrors()()
 	err := C.OGR_DS_DeleteLayer(ds.cval, C.int(index))
-	return error(err)
+	return ogrError(err)
 }
 
 // Fetch the driver that the data source was opened with
 func (ds DataSource) Driver() OGRDriver {
+	defer runtime.KeepAlive(ds)
 	driver := C.OGR_DS_GetDriver(ds.cval)
 	return OGRDriver{driver}
 }
@@ -1654,6 +1954,8 @@ func (ds DataSource) CreateLayer(
 	geomType GeometryType,
 	options []string,
 ) Layer {
+	defer runtime.KeepAlive(ds)
+	defer runtime.KeepAlive(sr)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 
@@ -1672,7 +1974,7 @@ func (ds DataSource) CreateLayer(
 		C.OGRwkbGeometryType(geomType),
 		(**C.char)(unsafe.Pointer(&opts[0])),
 	)
-	return Layer{layer}
+	return Layer{layer, ds.owner}
 }
 
 // Duplicate an existing layer
@@ -1681,6 +1983,8 @@ func (ds DataSource) CopyLayer(
 	name string,
 	options []string,
 ) Layer {
+	defer runtime.KeepAlive(ds)
+	defer runtime.KeepAlive(source)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 
@@ -1698,11 +2002,12 @@ func (ds DataSource) CopyLayer(
 		cName,
 		(**C.char)(unsafe.Pointer(&opts[0])),
 	)
-	return Layer{layer}
+	return Layer{layer, ds.owner}
 }
 
 // Test if the data source has the indicated capability
 func (ds DataSource) TestCapability(capability string) bool {
+	defer runtime.KeepAlive(ds)
 	cString := C.CString(capability)
 	defer C.free(unsafe.Pointer(cString))
 	val := C.OGR_DS_TestCapability(ds.cval, cString)
@@ -1711,24 +2016,30 @@ func (ds DataSource) TestCapability(capability string) bool {
 
 // Execute an SQL statement against the data source
 func (ds DataSource) ExecuteSQL(sql string, filter Geometry, dialect string) Layer {
+	defer runtime.KeepAlive(ds)
+	defer runtime.KeepAlive(filter)
 	cSQL := C.CString(sql)
 	defer C.free(unsafe.Pointer(cSQL))
 	cDialect := C.CString(dialect)
 	defer C.free(unsafe.Pointer(cDialect))
 
 	layer := C.OGR_DS_ExecuteSQL(ds.cval, cSQL, filter.cval, cDialect)
-	return Layer{layer}
+	return Layer{layer, ds.owner}
 }
 
 // Release the results of ExecuteSQL
 func (ds DataSource) ReleaseResultSet(layer Layer) {
+	defer runtime.KeepAlive(ds)
+	defer runtime.KeepAlive(layer)
 	C.OGR_DS_ReleaseResultSet(ds.cval, layer.cval)
 }
 
 // Flush pending changes to the data source
 func (ds DataSource) Sync() error {
+	defer runtime.KeepAlive(ds)
+	defer captureErrors()()
 	err := C.OGR_DS_SyncToDisk(ds.cval)
-	return error(err)
+	return ogrError(err)
 }
 
 /* -------------------------------------------------------------------- */
@@ -1750,7 +2061,7 @@ func (driver OGRDriver) Open(filename string, update int) (newDS DataSource, ok
 	cFilename := C.CString(filename)
 	defer C.free(unsafe.Pointer(cFilename))
 	ds := C.OGR_Dr_Open(driver.cval, cFilename, C.int(update))
-	return DataSource{ds}, ds != nil
+	return ownDataSource(ds), ds != nil
 }
 
 // Test if this driver supports the named capability
@@ -1775,11 +2086,12 @@ func (driver OGRDriver) Create(name string, options []string) (newDS DataSource,
 	opts[length] = (*C.char)(unsafe.Pointer(nil))
 
 	ds := C.OGR_Dr_CreateDataSource(driver.cval, cName, (**C.char)(unsafe.Pointer(&opts[0])))
-	return DataSource{ds}, ds != nil
+	return ownDataSource(ds), ds != nil
 }
 
 // Create a new datasource with this driver by copying all layers of the existing datasource
 func (driver OGRDriver) Copy(source DataSource, name string, options []string) (newDS DataSource, ok bool) {
+	defer runtime.KeepAlive(source)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 
@@ -1792,15 +2104,16 @@ func (driver OGRDriver) Copy(source DataSource, name string, options []string) (
 	opts[length] = (*C.char)(unsafe.Pointer(nil))
 
 	ds := C.OGR_Dr_CopyDataSource(driver.cval, source.cval, cName, (**C.char)(unsafe.Pointer(&opts[0])))
-	return DataSource{ds}, ds != nil
+	return ownDataSource(ds), ds != nil
 }
 
 // Delete a data source
 func (driver OGRDriver) Delete(filename string) error {
 	cFilename := C.CString(filename)
 	defer C.free(unsafe.Pointer(cFilename))
+	defer captureErrors()()
 	err := C.OGR_Dr_DeleteDataSource(driver.cval, cFilename)
-	return error(err)
+	return ogrError(err)
 }
 
 // Add a driver to the list of registered drivers
@@ -0,0 +1,230 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gdal
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// Create an in-memory data source with a single point layer holding one
+// integer field and one feature
+func createMemoryLayer(t *testing.T) (DataSource, Layer, Feature) {
+	driver := OGRDriverByName("Memory")
+	ds, ok := driver.Create("test", nil)
+	if !ok {
+		t.Fatal("failed to create Memory data source")
+	}
+	layer := ds.CreateLayer("points", SpatialReference{}, GT_Point, nil)
+
+	fd := CreateFieldDefinition("value", FT_Integer)
+	defer fd.Destroy()
+	if err := layer.CreateField(fd, false); err != nil {
+		t.Fatalf("CreateField: %v", err)
+	}
+
+	feature := layer.Definition().Create()
+	feature.SetFieldInteger(0, 1)
+	if err := layer.Create(feature); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return ds, layer, feature
+}
+
+func TestOGRNilOnSuccess(t *testing.T) {
+	ds, layer, feature := createMemoryLayer(t)
+	defer ds.Destroy()
+	defer feature.Destroy()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"Layer.SetAttributeFilter", func() error { return layer.SetAttributeFilter("value = 1") }},
+		{"Layer.SetAttributeFilter reset", func() error { return layer.SetAttributeFilter("") }},
+		{"Layer.SetNextByIndex", func() error { return layer.SetNextByIndex(0) }},
+		{"Layer.SetFeature", func() error { return layer.SetFeature(feature) }},
+		{"Layer.CreateField", func() error {
+			fd := CreateFieldDefinition("name", FT_String)
+			defer fd.Destroy()
+			return layer.CreateField(fd, false)
+		}},
+		{"Layer.ReorderField", func() error { return layer.ReorderField(1, 0) }},
+		{"Layer.DeleteField", func() error { return layer.DeleteField(0) }},
+		{"Layer.Sync", func() error { return layer.Sync() }},
+		{"Layer.Delete", func() error { return layer.Delete(feature.FID()) }},
+		{"DataSource.Sync", func() error { return ds.Sync() }},
+		{"Feature.SetFID", func() error { return feature.SetFID(42) }},
+		{"Feature.SetGeometry", func() error {
+			geom, err := CreateFromWKT("POINT (1 2)", SpatialReference{})
+			if err != nil {
+				return err
+			}
+			defer geom.Destroy()
+			return feature.SetGeometry(geom)
+		}},
+		{"Geometry.FromWKT", func() error {
+			geom := Create(GT_Point)
+			defer geom.Destroy()
+			return geom.FromWKT("POINT (3 4)")
+		}},
+		{"Geometry.FromWKB", func() error {
+			geom, err := CreateFromWKT("POINT (3 4)", SpatialReference{})
+			if err != nil {
+				return err
+			}
+			defer geom.Destroy()
+			wkb, err := geom.ToWKB(BO_NDR)
+			if err != nil {
+				return err
+			}
+			return geom.FromWKB(wkb, len(wkb))
+		}},
+	}
+
+	for _, test := range tests {
+		if err := test.call(); err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+		}
+	}
+}
+
+func TestOGRErrors(t *testing.T) {
+	ds, layer, feature := createMemoryLayer(t)
+	defer ds.Destroy()
+	defer feature.Destroy()
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"Geometry.FromWKB empty", func() error {
+			geom := Create(GT_Point)
+			defer geom.Destroy()
+			return geom.FromWKB(nil, 0)
+		}, OGRERR_NOT_ENOUGH_DATA},
+		{"CreateFromWKB empty", func() error {
+			_, err := CreateFromWKB(nil, SpatialReference{}, 0)
+			return err
+		}, OGRERR_NOT_ENOUGH_DATA},
+		{"Layer.SetAttributeFilter invalid", func() error {
+			return layer.SetAttributeFilter("no_such_field = 1")
+		}, OGRERR_CORRUPT_DATA},
+		{"Layer.DeleteField out of range", func() error {
+			return layer.DeleteField(10)
+		}, OGRERR_FAILURE},
+	}
+
+	for _, test := range tests {
+		err := test.call()
+		if err == nil {
+			t.Errorf("%s: expected error", test.name)
+			continue
+		}
+		if !errors.Is(err, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, err, test.want)
+		}
+		var gdalErr *Error
+		if !errors.As(err, &gdalErr) {
+			t.Errorf("%s: %T is not a *gdal.Error", test.name, err)
+		}
+	}
+}
+
+func TestOwnership(t *testing.T) {
+	ds, layer, feature := createMemoryLayer(t)
+	defer feature.Destroy()
+
+	geom, err := CreateFromWKT("POINT (1 2)", SpatialReference{})
+	if err != nil {
+		t.Fatalf("CreateFromWKT: %v", err)
+	}
+	if err := feature.SetGeometryDirectly(geom); err != nil {
+		t.Fatalf("SetGeometryDirectly: %v", err)
+	}
+	// the feature owns the geometry now
+	geom.Destroy()
+
+	borrowed, ok := feature.Geometry()
+	if !ok {
+		t.Fatal("feature has no geometry")
+	}
+	borrowed.Destroy()
+	layer.Definition().Destroy()
+	if wkt, err := borrowed.ToWKT(); err != nil || wkt != "POINT (1 2)" {
+		t.Errorf("borrowed geometry was released: %q, %v", wkt, err)
+	}
+	if count := layer.Definition().FieldCount(); count != 1 {
+		t.Errorf("layer definition was released: %d fields", count)
+	}
+
+	clone := feature.Clone()
+	clone.Destroy()
+	clone.Destroy()
+
+	ds.Destroy()
+	ds.Destroy()
+}
+
+func TestFinalizers(t *testing.T) {
+	SetFinalizers(true)
+	defer SetFinalizers(false)
+
+	freed := make(chan struct{})
+	func() {
+		own(func() { close(freed) })
+	}()
+	for i := 0; ; i++ {
+		runtime.GC()
+		select {
+		case <-freed:
+		case <-time.After(10 * time.Millisecond):
+			if i < 100 {
+				continue
+			}
+			t.Fatal("unreachable owned object not released")
+		}
+		break
+	}
+
+	// borrowed handles keep the objects they come from alive, even when
+	// the handles of these objects are gone
+	data := []uint8{1, 2, 3, 4}
+	for i := 0; i < 20; i++ {
+		band := createMemoryRaster(t, 2, 2, Byte).RasterBand(1)
+		runtime.GC()
+		if err := WriteWindow(band, 0, 0, 2, 2, data); err != nil {
+			t.Fatal(err)
+		}
+		runtime.GC()
+		got, err := ReadWindow[uint8](band, 0, 0, 2, 2)
+		if err != nil || !reflect.DeepEqual(got, data) {
+			t.Fatalf("ReadWindow through borrowed band: got %v, %v", got, err)
+		}
+	}
+
+	geom := func() Geometry {
+		ds, _, feature := createMemoryLayer(t)
+		defer ds.Destroy()
+		point, err := CreateFromWKT("POINT (1 2)", SpatialReference{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := feature.SetGeometryDirectly(point); err != nil {
+			t.Fatal(err)
+		}
+		geom, _ := feature.Geometry()
+		return geom
+	}()
+	runtime.GC()
+	runtime.GC()
+	if wkt, err := geom.ToWKT(); err != nil || wkt != "POINT (1 2)" {
+		t.Errorf("geometry of collected feature: got %q, %v", wkt, err)
+	}
+}
@@ -3,15 +3,12 @@ package gdal
 /*
 #include "go_gdal.h"
 #include "gdal_version.h"
-
-#cgo linux  CFLAGS: -I/usr/include/gdal
-#cgo linux  LDFLAGS: -lgdal
-#cgo darwin pkg-config: gdal
-#cgo windows LDFLAGS: -lgdal.dll
 */
 import "C"
 import (
+	"math"
 	"reflect"
+	"runtime"
 	"unsafe"
 )
 
@@ -20,7 +17,16 @@ import (
 /* -------------------------------------------------------------------- */
 
 type SpatialReference struct {
-	cval C.OGRSpatialReferenceH
+	cval  C.OGRSpatialReferenceH
+	owner *owner
+}
+
+// Wrap a spatial reference handle owned by the caller
+func ownSpatialReference(cval C.OGRSpatialReferenceH) SpatialReference {
+	if cval == nil {
+		return SpatialReference{}
+	}
+	return SpatialReference{cval, own(func() { C.OSRRelease(cval) })}
 }
 
 // Create a new SpatialReference
@@ -28,16 +34,18 @@ func CreateSpatialReference(wkt string) SpatialReference {
 	cString := C.CString(wkt)
 	defer C.free(unsafe.Pointer(cString))
 	sr := C.OSRNewSpatialReference(cString)
-	return SpatialReference{sr}
+	return ownSpatialReference(sr)
 }
 
 // Initialize SRS based on WKT string
 func (sr SpatialReference) FromWKT(wkt string) error {
+	defer runtime.KeepAlive(sr)
 	cString := C.CString(wkt)
 	defer C.free(unsafe.Pointer(cString))
+	defer captureErrors()()
 	err := C.OSRImportFromWkt(sr.cval, &cString)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -45,10 +53,12 @@ func (sr SpatialReference) FromWKT(wkt string) error {
 
 // Export coordinate system to WKT
 func (sr SpatialReference) ToWKT() (string, error) {
+	defer runtime.KeepAlive(sr)
 	var p *C.char
+	defer captureErrors()()
 	err := C.OSRExportToWkt(sr.cval, &p)
 	if err != 0 {
-		return "", error(err)
+		return "", ogrError(err)
 	}
 
 	wkt := C.GoString(p)
@@ -57,17 +67,21 @@ func (sr SpatialReference) ToWKT() (string, error) {
 
 // Export coordinate system to a nicely formatted WKT string
 func (sr SpatialReference) ToPrettyWKT(simplify bool) (string, error) {
+	defer runtime.KeepAlive(sr)
 	var p *C.char
+	defer captureErrors()()
 	err := C.OSRExportToPrettyWkt(sr.cval, &p, BoolToCInt(simplify))
 	wkt := C.GoString(p)
-	return wkt, error(err)
+	return wkt, ogrError(err)
 }
 
 // Initialize SRS based on EPSG code
 func (sr SpatialReference) FromEPSG(code int) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRImportFromEPSG(sr.cval, C.int(code))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -75,53 +89,73 @@ func (sr SpatialReference) FromEPSG(code int) error {
 
 // Initialize SRS based on EPSG code, using EPSG lat/long ordering
 func (sr SpatialReference) FromEPSGA(code int) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRImportFromEPSGA(sr.cval, C.int(code))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
 }
 
-// Destroy the spatial reference
+// Destroy the spatial reference.  The object is only deleted once the
+// geometries and layers referencing it let it go, and spatial references
+// owned by a geometry or a layer are left alone.
 func (sr SpatialReference) Destroy() {
-	C.OSRDestroySpatialReference(sr.cval)
+	sr.owner.release()
 }
 
 // Make a duplicate of this spatial reference
 func (sr SpatialReference) Clone() SpatialReference {
+	defer runtime.KeepAlive(sr)
 	newSR := C.OSRClone(sr.cval)
-	return SpatialReference{newSR}
+	return ownSpatialReference(newSR)
 }
 
 // Make a duplicate of the GEOGCS node of this spatial reference
 func (sr SpatialReference) CloneGeogCS() SpatialReference {
+	defer runtime.KeepAlive(sr)
 	newSR := C.OSRCloneGeogCS(sr.cval)
-	return SpatialReference{newSR}
+	return ownSpatialReference(newSR)
 }
 
-// Increments the reference count by one, returning reference count
+// Increments the reference count by one, returning reference count.  The
+// reference is dropped by Release or Dereference.
 func (sr SpatialReference) Reference() int {
+	defer runtime.KeepAlive(sr)
 	count := C.OSRReference(sr.cval)
+	sr.owner.addRef()
 	return int(count)
 }
 
 // Decrements the reference count by one, returning reference count
 func (sr SpatialReference) Dereference() int {
+	defer runtime.KeepAlive(sr)
+	sr.owner.dropRef()
 	count := C.OSRDereference(sr.cval)
 	return int(count)
 }
 
-// Decrements the reference count by one and destroy if zero
+// Decrements the reference count by one and destroy if zero.  Release
+// first drops the references taken with Reference, then the reference an
+// owned handle holds, as Destroy does; further calls do nothing.
 func (sr SpatialReference) Release() {
-	C.OSRRelease(sr.cval)
+	defer runtime.KeepAlive(sr)
+	if sr.owner.dropRef() {
+		C.OSRRelease(sr.cval)
+		return
+	}
+	sr.owner.release()
 }
 
 // Validate spatial reference tokens
 func (sr SpatialReference) Validate() error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRValidate(sr.cval)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -129,9 +163,11 @@ func (sr SpatialReference) Validate() error {
 
 // Correct parameter ordering to match CT specification
 func (sr SpatialReference) FixupOrdering() error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRFixupOrdering(sr.cval)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -139,9 +175,11 @@ func (sr SpatialReference) FixupOrdering() error {
 
 // Fix up spatial reference as needed
 func (sr SpatialReference) Fixup() error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRFixup(sr.cval)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -149,9 +187,11 @@ func (sr SpatialReference) Fixup() error {
 
 // Strip OGC CT parameters
 func (sr SpatialReference) StripCTParams() error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRStripCTParms(sr.cval)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -159,11 +199,13 @@ func (sr SpatialReference) StripCTParams() error {
 
 // Import PROJ.4 coordinate string
 func (sr SpatialReference) FromProj4(input string) error {
+	defer runtime.KeepAlive(sr)
 	cString := C.CString(input)
 	defer C.free(unsafe.Pointer(cString))
+	defer captureErrors()()
 	err := C.OSRImportFromProj4(sr.cval, cString)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -171,10 +213,12 @@ func (sr SpatialReference) FromProj4(input string) error {
 
 // Export coordinate system in PROJ.4 format
 func (sr SpatialReference) ToProj4() (string, error) {
+	defer runtime.KeepAlive(sr)
 	var p *C.char
+	defer captureErrors()()
 	err := C.OSRExportToProj4(sr.cval, &p)
 	if err != 0 {
-		return "", error(err)
+		return "", ogrError(err)
 	}
 
 	proj4 := C.GoString(p)
@@ -183,11 +227,13 @@ func (sr SpatialReference) ToProj4() (string, error) {
 
 // Import coordinate system from ESRI .prj formats
 func (sr SpatialReference) FromESRI(input string) error {
+	defer runtime.KeepAlive(sr)
 	cString := C.CString(input)
 	defer C.free(unsafe.Pointer(cString))
+	defer captureErrors()()
 	err := C.OSRImportFromProj4(sr.cval, cString)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -195,18 +241,20 @@ func (sr SpatialReference) FromESRI(input string) error {
 
 // Import coordinate system from PCI projection definition
 func (sr SpatialReference) FromPCI(proj, units string, params []float64) error {
+	defer runtime.KeepAlive(sr)
 	cProj := C.CString(proj)
 	defer C.free(unsafe.Pointer(cProj))
 	cUnits := C.CString(units)
 	defer C.free(unsafe.Pointer(cUnits))
 
+	defer captureErrors()()
 	err := C.OSRImportFromPCI(
 		sr.cval,
 		cProj,
 		cUnits,
 		(*C.double)(unsafe.Pointer(&params[0])))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -214,6 +262,8 @@ func (sr SpatialReference) FromPCI(proj, units string, params []float64) error {
 
 // Import coordinate system from USGS projection definition
 func (sr SpatialReference) FromUSGS(projsys, zone int, params []float64, datum int) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRImportFromUSGS(
 		sr.cval,
 		C.long(projsys),
@@ -221,7 +271,7 @@ func (sr SpatialReference) FromUSGS(projsys, zone int, params []float64, datum i
 		(*C.double)(unsafe.Pointer(&params[0])),
 		C.long(datum))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -229,11 +279,13 @@ func (sr SpatialReference) FromUSGS(projsys, zone int, params []float64, datum i
 
 // Import coordinate system from XML format (GML only currently)
 func (sr SpatialReference) FromXML(xml string) error {
+	defer runtime.KeepAlive(sr)
 	cXml := C.CString(xml)
 	defer C.free(unsafe.Pointer(cXml))
+	defer captureErrors()()
 	err := C.OSRImportFromXML(sr.cval, cXml)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -241,6 +293,7 @@ func (sr SpatialReference) FromXML(xml string) error {
 
 // Import coordinate system from ERMapper projection definitions
 func (sr SpatialReference) FromERM(proj, datum, units string) error {
+	defer runtime.KeepAlive(sr)
 	cProj := C.CString(proj)
 	defer C.free(unsafe.Pointer(cProj))
 	cDatum := C.CString(datum)
@@ -248,9 +301,10 @@ func (sr SpatialReference) FromERM(proj, datum, units string) error {
 	cUnits := C.CString(units)
 	defer C.free(unsafe.Pointer(cUnits))
 
+	defer captureErrors()()
 	err := C.OSRImportFromERM(sr.cval, cProj, cDatum, cUnits)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -258,11 +312,13 @@ func (sr SpatialReference) FromERM(proj, datum, units string) error {
 
 // Import coordinate system from a URL
 func (sr SpatialReference) FromURL(url string) error {
+	defer runtime.KeepAlive(sr)
 	cURL := C.CString(url)
 	defer C.free(unsafe.Pointer(cURL))
+	defer captureErrors()()
 	err := C.OSRImportFromXML(sr.cval, cURL)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -270,10 +326,12 @@ func (sr SpatialReference) FromURL(url string) error {
 
 // Export coordinate system in PCI format
 func (sr SpatialReference) ToPCI() (proj, units string, params []float64, errVal error) {
+	defer runtime.KeepAlive(sr)
 	var p, u *C.char
+	defer captureErrors()()
 	err := C.OSRExportToPCI(sr.cval, &p, &u, (**C.double)(unsafe.Pointer(&params[0])))
 	if err != 0 {
-		return "", "", nil, error(err)
+		return "", "", nil, ogrError(err)
 	}
 
 	proj = C.GoString(p)
@@ -291,6 +349,8 @@ func (sr SpatialReference) ToPCI() (proj, units string, params []float64, errVal
 
 // Export coordinate system to USGS GCTP projection definition
 func (sr SpatialReference) ToUSGS() (proj, zone int, params []float64, datum int, errVal error) {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRExportToUSGS(
 		sr.cval,
 		(*C.long)(unsafe.Pointer(&proj)),
@@ -298,7 +358,7 @@ func (sr SpatialReference) ToUSGS() (proj, zone int, params []float64, datum int
 		(**C.double)(unsafe.Pointer(&params[0])),
 		(*C.long)(unsafe.Pointer(&datum)))
 	if err != 0 {
-		return proj, zone, nil, datum, error(err)
+		return proj, zone, nil, datum, ogrError(err)
 	}
 
 	header := (*reflect.SliceHeader)((unsafe.Pointer(&params)))
@@ -310,10 +370,12 @@ func (sr SpatialReference) ToUSGS() (proj, zone int, params []float64, datum int
 
 // Export coordinate system in XML format
 func (sr SpatialReference) ToXML() (xml string, errVal error) {
+	defer runtime.KeepAlive(sr)
 	var x *C.char
+	defer captureErrors()()
 	err := C.OSRExportToXML(sr.cval, &x, nil)
 	if err != 0 {
-		return "", error(err)
+		return "", ogrError(err)
 	}
 
 	xml = C.GoString(x)
@@ -324,15 +386,17 @@ func (sr SpatialReference) ToXML() (xml string, errVal error) {
 
 // Export coordinate system in Mapinfo style CoordSys format
 func (sr SpatialReference) ToMICoordSys() (output string, errVal error) {
+	defer runtime.KeepAlive(sr)
 	var x *C.char
+	defer captureErrors()()
 	err := C.OSRExportToMICoordSys(sr.cval, &x)
 	if err != 0 {
-		return "", error(err)
+		return "", ogrError(err)
 	}
 
 	output = C.GoString(x)
 	C.free(unsafe.Pointer(x))
-	return output, error(err)
+	return output, ogrError(err)
 }
 
 // Export coordinate system in ERMapper format
@@ -340,9 +404,11 @@ func (sr SpatialReference) ToMICoordSys() (output string, errVal error) {
 
 // Convert in place to ESRI WKT format
 func (sr SpatialReference) MorphToESRI() error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRMorphToESRI(sr.cval)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -350,9 +416,11 @@ func (sr SpatialReference) MorphToESRI() error {
 
 // Convert in place from ESRI WKT format
 func (sr SpatialReference) MorphFromESRI() error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRMorphFromESRI(sr.cval)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -360,6 +428,7 @@ func (sr SpatialReference) MorphFromESRI() error {
 
 // Fetch indicated attribute of named node
 func (sr SpatialReference) AttrValue(key string, child int) (value string, ok bool) {
+	defer runtime.KeepAlive(sr)
 	cKey := C.CString(key)
 	defer C.free(unsafe.Pointer(cKey))
 	val := C.OSRGetAttrValue(sr.cval, cKey, C.int(child))
@@ -368,13 +437,15 @@ func (sr SpatialReference) AttrValue(key string, child int) (value string, ok bo
 
 // Set attribute value in spatial reference
 func (sr SpatialReference) SetAttrValue(path, value string) error {
+	defer runtime.KeepAlive(sr)
 	cPath := C.CString(path)
 	defer C.free(unsafe.Pointer(cPath))
 	cValue := C.CString(value)
 	defer C.free(unsafe.Pointer(cValue))
+	defer captureErrors()()
 	err := C.OSRSetAttrValue(sr.cval, cPath, cValue)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -382,11 +453,13 @@ func (sr SpatialReference) SetAttrValue(path, value string) error {
 
 // Set the angular units for the geographic coordinate system
 func (sr SpatialReference) SetAngularUnits(units string, radians float64) error {
+	defer runtime.KeepAlive(sr)
 	cUnits := C.CString(units)
 	defer C.free(unsafe.Pointer(cUnits))
+	defer captureErrors()()
 	err := C.OSRSetAngularUnits(sr.cval, cUnits, C.double(radians))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -394,6 +467,7 @@ func (sr SpatialReference) SetAngularUnits(units string, radians float64) error
 
 // Fetch the angular units for the geographic coordinate system
 func (sr SpatialReference) AngularUnits() (string, float64) {
+	defer runtime.KeepAlive(sr)
 	var x *C.char
 	factor := C.OSRGetAngularUnits(sr.cval, &x)
 	defer C.free(unsafe.Pointer(x))
@@ -402,11 +476,13 @@ func (sr SpatialReference) AngularUnits() (string, float64) {
 
 // Set the linear units for the projection
 func (sr SpatialReference) SetLinearUnits(name string, toMeters float64) error {
+	defer runtime.KeepAlive(sr)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	err := C.OSRSetLinearUnits(sr.cval, cName, C.double(toMeters))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -414,13 +490,15 @@ func (sr SpatialReference) SetLinearUnits(name string, toMeters float64) error {
 
 // Set the linear units for the target node
 func (sr SpatialReference) SetTargetLinearUnits(target, units string, toMeters float64) error {
+	defer runtime.KeepAlive(sr)
 	cTarget := C.CString(target)
 	defer C.free(unsafe.Pointer(cTarget))
 	cUnits := C.CString(units)
 	defer C.free(unsafe.Pointer(cUnits))
+	defer captureErrors()()
 	err := C.OSRSetTargetLinearUnits(sr.cval, cTarget, cUnits, C.double(toMeters))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -428,11 +506,13 @@ func (sr SpatialReference) SetTargetLinearUnits(target, units string, toMeters f
 
 // Set the linear units for the target node and update all existing linear parameters
 func (sr SpatialReference) SetLinearUnitsAndUpdateParameters(name string, toMeters float64) error {
+	defer runtime.KeepAlive(sr)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	err := C.OSRSetLinearUnitsAndUpdateParameters(sr.cval, cName, C.double(toMeters))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -440,6 +520,7 @@ func (sr SpatialReference) SetLinearUnitsAndUpdateParameters(name string, toMete
 
 // Fetch linear projection units
 func (sr SpatialReference) LinearUnits() (string, float64) {
+	defer runtime.KeepAlive(sr)
 	var x *C.char
 	factor := C.OSRGetLinearUnits(sr.cval, &x)
 	defer C.free(unsafe.Pointer(x))
@@ -448,6 +529,7 @@ func (sr SpatialReference) LinearUnits() (string, float64) {
 
 // Fetch linear units for target
 func (sr SpatialReference) TargetLinearUnits(target string) (string, float64) {
+	defer runtime.KeepAlive(sr)
 	cTarget := C.CString(target)
 	defer C.free(unsafe.Pointer(cTarget))
 	var x *C.char
@@ -458,6 +540,7 @@ func (sr SpatialReference) TargetLinearUnits(target string) (string, float64) {
 
 // Fetch prime meridian information
 func (sr SpatialReference) PrimeMeridian() (string, float64) {
+	defer runtime.KeepAlive(sr)
 	var x *C.char
 	offset := C.OSRGetPrimeMeridian(sr.cval, &x)
 	defer C.free(unsafe.Pointer(x))
@@ -466,65 +549,79 @@ func (sr SpatialReference) PrimeMeridian() (string, float64) {
 
 // Return true if geographic coordinate system
 func (sr SpatialReference) IsGeographic() bool {
+	defer runtime.KeepAlive(sr)
 	val := C.OSRIsGeographic(sr.cval)
 	return val != 0
 }
 
 // Return true if local coordinate system
 func (sr SpatialReference) IsLocal() bool {
+	defer runtime.KeepAlive(sr)
 	val := C.OSRIsLocal(sr.cval)
 	return val != 0
 }
 
 // Return true if projected coordinate system
 func (sr SpatialReference) IsProjected() bool {
+	defer runtime.KeepAlive(sr)
 	val := C.OSRIsProjected(sr.cval)
 	return val != 0
 }
 
 // Return true if compound coordinate system
 func (sr SpatialReference) IsCompound() bool {
+	defer runtime.KeepAlive(sr)
 	val := C.OSRIsCompound(sr.cval)
 	return val != 0
 }
 
 // Return true if geocentric coordinate system
 func (sr SpatialReference) IsGeocentric() bool {
+	defer runtime.KeepAlive(sr)
 	val := C.OSRIsGeocentric(sr.cval)
 	return val != 0
 }
 
 // Return true if vertical coordinate system
 func (sr SpatialReference) IsVertical() bool {
+	defer runtime.KeepAlive(sr)
 	val := C.OSRIsVertical(sr.cval)
 	return val != 0
 }
 
 // Return true if the geographic coordinate systems match
 func (sr SpatialReference) IsSameGeographicCS(other SpatialReference) bool {
+	defer runtime.KeepAlive(sr)
+	defer runtime.KeepAlive(other)
 	val := C.OSRIsSameGeogCS(sr.cval, other.cval)
 	return val != 0
 }
 
 // Return true if the vertical coordinate systems match
 func (sr SpatialReference) IsSameVerticalCS(other SpatialReference) bool {
+	defer runtime.KeepAlive(sr)
+	defer runtime.KeepAlive(other)
 	val := C.OSRIsSameVertCS(sr.cval, other.cval)
 	return val != 0
 }
 
 // Return true if the coordinate systems describe the same system
 func (sr SpatialReference) IsSame(other SpatialReference) bool {
+	defer runtime.KeepAlive(sr)
+	defer runtime.KeepAlive(other)
 	val := C.OSRIsSame(sr.cval, other.cval)
 	return val != 0
 }
 
 // Set the user visible local CS name
 func (sr SpatialReference) SetLocalCS(name string) error {
+	defer runtime.KeepAlive(sr)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	err := C.OSRSetLocalCS(sr.cval, cName)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -532,11 +629,13 @@ func (sr SpatialReference) SetLocalCS(name string) error {
 
 // Set the user visible projected CS name
 func (sr SpatialReference) SetProjectedCS(name string) error {
+	defer runtime.KeepAlive(sr)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	err := C.OSRSetProjCS(sr.cval, cName)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -544,11 +643,13 @@ func (sr SpatialReference) SetProjectedCS(name string) error {
 
 // Set the user visible geographic CS name
 func (sr SpatialReference) SetGeocentricCS(name string) error {
+	defer runtime.KeepAlive(sr)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	err := C.OSRSetGeocCS(sr.cval, cName)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -556,11 +657,13 @@ func (sr SpatialReference) SetGeocentricCS(name string) error {
 
 // Set geographic CS based on well known name
 func (sr SpatialReference) SetWellKnownGeographicCS(name string) error {
+	defer runtime.KeepAlive(sr)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	err := C.OSRSetWellKnownGeogCS(sr.cval, cName)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -568,11 +671,13 @@ func (sr SpatialReference) SetWellKnownGeographicCS(name string) error {
 
 // Set spatial reference from various text formats
 func (sr SpatialReference) SetFromUserInput(name string) error {
+	defer runtime.KeepAlive(sr)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	err := C.OSRSetFromUserInput(sr.cval, cName)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -580,9 +685,12 @@ func (sr SpatialReference) SetFromUserInput(name string) error {
 
 // Copy geographic CS from another spatial reference
 func (sr SpatialReference) CopyGeographicCSFrom(other SpatialReference) error {
+	defer runtime.KeepAlive(sr)
+	defer runtime.KeepAlive(other)
+	defer captureErrors()()
 	err := C.OSRCopyGeogCSFrom(sr.cval, other.cval)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -590,6 +698,8 @@ func (sr SpatialReference) CopyGeographicCSFrom(other SpatialReference) error {
 
 // Set the Bursa-Wolf conversion to WGS84
 func (sr SpatialReference) SetTOWGS84(dx, dy, dz, ex, ey, ez, ppm float64) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetTOWGS84(
 		sr.cval,
 		C.double(dx),
@@ -600,7 +710,7 @@ func (sr SpatialReference) SetTOWGS84(dx, dy, dz, ex, ey, ez, ppm float64) error
 		C.double(ez),
 		C.double(ppm))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -608,9 +718,11 @@ func (sr SpatialReference) SetTOWGS84(dx, dy, dz, ex, ey, ez, ppm float64) error
 
 // Fetch the TOWGS84 parameters if available
 func (sr SpatialReference) TOWGS84() (coeff [7]float64, errVal error) {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRGetTOWGS84(sr.cval, (*C.double)(unsafe.Pointer(&coeff[0])), 7)
 	if err != 0 {
-		return coeff, error(err)
+		return coeff, ogrError(err)
 	}
 
 	return coeff, nil
@@ -621,11 +733,15 @@ func (sr SpatialReference) SetCompoundCS(
 	name string,
 	horizontal, vertical SpatialReference,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer runtime.KeepAlive(horizontal)
+	defer runtime.KeepAlive(vertical)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	err := C.OSRSetCompoundCS(sr.cval, cName, horizontal.cval, vertical.cval)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -640,6 +756,7 @@ func (sr SpatialReference) SetGeographicCS(
 	angularUnits string,
 	toRadians float64,
 ) error {
+	defer runtime.KeepAlive(sr)
 	cGeogName := C.CString(geogName)
 	defer C.free(unsafe.Pointer(cGeogName))
 	cDatumName := C.CString(datumName)
@@ -650,6 +767,7 @@ func (sr SpatialReference) SetGeographicCS(
 	defer C.free(unsafe.Pointer(cPMName))
 	cAngularUnits := C.CString(angularUnits)
 	defer C.free(unsafe.Pointer(cAngularUnits))
+	defer captureErrors()()
 	err := C.OSRSetGeogCS(
 		sr.cval,
 		cGeogName,
@@ -662,7 +780,7 @@ func (sr SpatialReference) SetGeographicCS(
 		cAngularUnits,
 		C.double(toRadians))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -670,13 +788,15 @@ func (sr SpatialReference) SetGeographicCS(
 
 // Set up the vertical coordinate system
 func (sr SpatialReference) SetVerticalCS(csName, datumName string, datumType int) error {
+	defer runtime.KeepAlive(sr)
 	cCSName := C.CString(csName)
 	defer C.free(unsafe.Pointer(cCSName))
 	cDatumName := C.CString(datumName)
 	defer C.free(unsafe.Pointer(cDatumName))
+	defer captureErrors()()
 	err := C.OSRSetVertCS(sr.cval, cCSName, cDatumName, C.int(datumType))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -684,10 +804,12 @@ func (sr SpatialReference) SetVerticalCS(csName, datumName string, datumType int
 
 // Get spheroid semi-major axis
 func (sr SpatialReference) SemiMajorAxis() (float64, error) {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	var err C.OGRErr
 	axis := C.OSRGetSemiMajor(sr.cval, &err)
 	if err != 0 {
-		return float64(axis), error(err)
+		return float64(axis), ogrError(err)
 	}
 
 	return float64(axis), nil
@@ -695,10 +817,12 @@ func (sr SpatialReference) SemiMajorAxis() (float64, error) {
 
 // Get spheroid semi-minor axis
 func (sr SpatialReference) SemiMinorAxis() (float64, error) {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	var err C.OGRErr
 	axis := C.OSRGetSemiMinor(sr.cval, &err)
 	if err != 0 {
-		return float64(axis), error(err)
+		return float64(axis), ogrError(err)
 	}
 
 	return float64(axis), nil
@@ -706,10 +830,12 @@ func (sr SpatialReference) SemiMinorAxis() (float64, error) {
 
 // Get spheroid inverse flattening axis
 func (sr SpatialReference) InverseFlattening() (float64, error) {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	var err C.OGRErr
 	flat := C.OSRGetInvFlattening(sr.cval, &err)
 	if err != 0 {
-		return float64(flat), error(err)
+		return float64(flat), ogrError(err)
 	}
 
 	return float64(flat), nil
@@ -717,13 +843,15 @@ func (sr SpatialReference) InverseFlattening() (float64, error) {
 
 // Sets the authority for a node
 func (sr SpatialReference) SetAuthority(target, authority string, code int) error {
+	defer runtime.KeepAlive(sr)
 	cTarget := C.CString(target)
 	defer C.free(unsafe.Pointer(cTarget))
 	cAuthority := C.CString(authority)
 	defer C.free(unsafe.Pointer(cAuthority))
+	defer captureErrors()()
 	err := C.OSRSetAuthority(sr.cval, cTarget, cAuthority, C.int(code))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -731,6 +859,7 @@ func (sr SpatialReference) SetAuthority(target, authority string, code int) erro
 
 // Get the authority code for a node
 func (sr SpatialReference) AuthorityCode(target string) string {
+	defer runtime.KeepAlive(sr)
 	cTarget := C.CString(target)
 	defer C.free(unsafe.Pointer(cTarget))
 	code := C.OSRGetAuthorityCode(sr.cval, cTarget)
@@ -739,6 +868,7 @@ func (sr SpatialReference) AuthorityCode(target string) string {
 
 // Get the authority name for a node
 func (sr SpatialReference) AuthorityName(target string) string {
+	defer runtime.KeepAlive(sr)
 	cTarget := C.CString(target)
 	defer C.free(unsafe.Pointer(cTarget))
 	code := C.OSRGetAuthorityName(sr.cval, cTarget)
@@ -747,11 +877,13 @@ func (sr SpatialReference) AuthorityName(target string) string {
 
 // Set a projection by name
 func (sr SpatialReference) SetProjectionByName(name string) error {
+	defer runtime.KeepAlive(sr)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	err := C.OSRSetProjection(sr.cval, cName)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -759,11 +891,13 @@ func (sr SpatialReference) SetProjectionByName(name string) error {
 
 // Set a projection parameter value
 func (sr SpatialReference) SetProjectionParameter(name string, value float64) error {
+	defer runtime.KeepAlive(sr)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	err := C.OSRSetProjParm(sr.cval, cName, C.double(value))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -771,12 +905,14 @@ func (sr SpatialReference) SetProjectionParameter(name string, value float64) er
 
 // Fetch a projection parameter value
 func (sr SpatialReference) ProjectionParameter(name string, defaultValue float64) (float64, error) {
+	defer runtime.KeepAlive(sr)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	var err C.OGRErr
 	value := C.OSRGetProjParm(sr.cval, cName, C.double(defaultValue), &err)
 	if err != 0 {
-		return float64(value), error(err)
+		return float64(value), ogrError(err)
 	}
 
 	return float64(value), nil
@@ -784,11 +920,13 @@ func (sr SpatialReference) ProjectionParameter(name string, defaultValue float64
 
 // Set a projection parameter with a normalized value
 func (sr SpatialReference) SetNormalizedProjectionParameter(name string, value float64) error {
+	defer runtime.KeepAlive(sr)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	err := C.OSRSetNormProjParm(sr.cval, cName, C.double(value))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -798,12 +936,14 @@ func (sr SpatialReference) SetNormalizedProjectionParameter(name string, value f
 func (sr SpatialReference) NormalizedProjectionParameter(
 	name string, defaultValue float64,
 ) (float64, error) {
+	defer runtime.KeepAlive(sr)
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	var err C.OGRErr
 	value := C.OSRGetProjParm(sr.cval, cName, C.double(defaultValue), &err)
 	if err != 0 {
-		return float64(value), error(err)
+		return float64(value), ogrError(err)
 	}
 
 	return float64(value), nil
@@ -811,9 +951,11 @@ func (sr SpatialReference) NormalizedProjectionParameter(
 
 // Set UTM projection definition
 func (sr SpatialReference) SetUTM(zone int, north bool) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetUTM(sr.cval, C.int(zone), BoolToCInt(north))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -821,6 +963,7 @@ func (sr SpatialReference) SetUTM(zone int, north bool) error {
 
 // Get UTM zone information
 func (sr SpatialReference) UTMZone() (zone int, north bool) {
+	defer runtime.KeepAlive(sr)
 	var northInt C.int
 	cZone := C.OSRGetUTMZone(sr.cval, &northInt)
 	return int(cZone), northInt != 0
@@ -828,9 +971,11 @@ func (sr SpatialReference) UTMZone() (zone int, north bool) {
 
 // Set State Plane projection definition
 func (sr SpatialReference) SetStatePlane(zone int, nad83 bool) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetStatePlane(sr.cval, C.int(zone), BoolToCInt(nad83))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -843,8 +988,10 @@ func (sr SpatialReference) SetStatePlaneWithUnits(
 	unitName string,
 	factor float64,
 ) error {
+	defer runtime.KeepAlive(sr)
 	cUnitName := C.CString(unitName)
 	defer C.free(unsafe.Pointer(cUnitName))
+	defer captureErrors()()
 	err := C.OSRSetStatePlaneWithUnits(
 		sr.cval,
 		C.int(zone),
@@ -852,7 +999,7 @@ func (sr SpatialReference) SetStatePlaneWithUnits(
 		cUnitName,
 		C.double(factor))
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -860,9 +1007,11 @@ func (sr SpatialReference) SetStatePlaneWithUnits(
 
 // Set EPSG authority info if possible
 func (sr SpatialReference) AutoIdentifyEPSG() error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRAutoIdentifyEPSG(sr.cval)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -870,6 +1019,7 @@ func (sr SpatialReference) AutoIdentifyEPSG() error {
 
 // Return true if EPSG feels this coordinate system should be treated as having lat/long coordinate ordering
 func (sr SpatialReference) EPSGTreatsAsLatLong() bool {
+	defer runtime.KeepAlive(sr)
 	val := C.OSREPSGTreatsAsLatLong(sr.cval)
 	return val != 0
 }
@@ -881,6 +1031,8 @@ func (sr SpatialReference) EPSGTreatsAsLatLong() bool {
 func (sr SpatialReference) SetACEA(
 	stdp1, stdp2, centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetACEA(
 		sr.cval,
 		C.double(stdp1),
@@ -891,7 +1043,7 @@ func (sr SpatialReference) SetACEA(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -899,6 +1051,8 @@ func (sr SpatialReference) SetACEA(
 
 // Set to Azimuthal Equidistant
 func (sr SpatialReference) SetAE(centerLat, centerLong, falseEasting, falseNorthing float64) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetAE(
 		sr.cval,
 		C.double(centerLat),
@@ -907,7 +1061,7 @@ func (sr SpatialReference) SetAE(centerLat, centerLong, falseEasting, falseNorth
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -915,6 +1069,8 @@ func (sr SpatialReference) SetAE(centerLat, centerLong, falseEasting, falseNorth
 
 // Set to Bonne
 func (sr SpatialReference) SetBonne(standardParallel, centralMeridian, falseEasting, falseNorthing float64) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetBonne(
 		sr.cval,
 		C.double(standardParallel),
@@ -923,7 +1079,7 @@ func (sr SpatialReference) SetBonne(standardParallel, centralMeridian, falseEast
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -931,6 +1087,8 @@ func (sr SpatialReference) SetBonne(standardParallel, centralMeridian, falseEast
 
 // Set to Cylindrical Equal Area
 func (sr SpatialReference) SetCEA(stdp1, centralMeridian, falseEasting, falseNorthing float64) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetCEA(
 		sr.cval,
 		C.double(stdp1),
@@ -939,7 +1097,7 @@ func (sr SpatialReference) SetCEA(stdp1, centralMeridian, falseEasting, falseNor
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -947,6 +1105,8 @@ func (sr SpatialReference) SetCEA(stdp1, centralMeridian, falseEasting, falseNor
 
 // Set to Cassini-Soldner
 func (sr SpatialReference) SetCS(centerLat, centerLong, falseEasting, falseNorthing float64) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetCS(
 		sr.cval,
 		C.double(centerLat),
@@ -955,7 +1115,7 @@ func (sr SpatialReference) SetCS(centerLat, centerLong, falseEasting, falseNorth
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -965,6 +1125,8 @@ func (sr SpatialReference) SetCS(centerLat, centerLong, falseEasting, falseNorth
 func (sr SpatialReference) SetEC(
 	stdp1, stdp2, centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetEC(
 		sr.cval,
 		C.double(stdp1),
@@ -975,7 +1137,7 @@ func (sr SpatialReference) SetEC(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -983,6 +1145,8 @@ func (sr SpatialReference) SetEC(
 
 // Set to Eckert I-VI
 func (sr SpatialReference) SetEckert(variation int, centralMeridian, falseEasting, falseNorthing float64) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetEckert(
 		sr.cval,
 		C.int(variation),
@@ -991,7 +1155,7 @@ func (sr SpatialReference) SetEckert(variation int, centralMeridian, falseEastin
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1001,6 +1165,8 @@ func (sr SpatialReference) SetEckert(variation int, centralMeridian, falseEastin
 func (sr SpatialReference) SetEquirectangular(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetEquirectangular(
 		sr.cval,
 		C.double(centerLat),
@@ -1009,7 +1175,7 @@ func (sr SpatialReference) SetEquirectangular(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1019,6 +1185,8 @@ func (sr SpatialReference) SetEquirectangular(
 func (sr SpatialReference) SetEquirectangularGeneralized(
 	centerLat, centerLong, psuedoStdParallel, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetEquirectangular2(
 		sr.cval,
 		C.double(centerLat),
@@ -1028,7 +1196,7 @@ func (sr SpatialReference) SetEquirectangularGeneralized(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1036,6 +1204,8 @@ func (sr SpatialReference) SetEquirectangularGeneralized(
 
 // Set to Gall Stereographic
 func (sr SpatialReference) SetGS(centralMeridian, falseEasting, falseNorthing float64) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetGS(
 		sr.cval,
 		C.double(centralMeridian),
@@ -1043,7 +1213,7 @@ func (sr SpatialReference) SetGS(centralMeridian, falseEasting, falseNorthing fl
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1051,6 +1221,8 @@ func (sr SpatialReference) SetGS(centralMeridian, falseEasting, falseNorthing fl
 
 // Set to Goode Homolosine
 func (sr SpatialReference) SetGH(centralMeridian, falseEasting, falseNorthing float64) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetGH(
 		sr.cval,
 		C.double(centralMeridian),
@@ -1058,7 +1230,7 @@ func (sr SpatialReference) SetGH(centralMeridian, falseEasting, falseNorthing fl
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1066,9 +1238,11 @@ func (sr SpatialReference) SetGH(centralMeridian, falseEasting, falseNorthing fl
 
 // Set to Interrupted Goode Homolosine
 func (sr SpatialReference) SetIGH() error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetIGH(sr.cval)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1078,6 +1252,8 @@ func (sr SpatialReference) SetIGH() error {
 func (sr SpatialReference) SetGEOS(
 	centralMeridian, satelliteHeight, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetGEOS(
 		sr.cval,
 		C.double(centralMeridian),
@@ -1086,7 +1262,7 @@ func (sr SpatialReference) SetGEOS(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1096,6 +1272,8 @@ func (sr SpatialReference) SetGEOS(
 func (sr SpatialReference) SetGSTM(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetGaussSchreiberTMercator(
 		sr.cval,
 		C.double(centerLat),
@@ -1105,7 +1283,7 @@ func (sr SpatialReference) SetGSTM(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1115,6 +1293,8 @@ func (sr SpatialReference) SetGSTM(
 func (sr SpatialReference) SetGnomonic(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetGnomonic(
 		sr.cval,
 		C.double(centerLat),
@@ -1123,7 +1303,7 @@ func (sr SpatialReference) SetGnomonic(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1133,6 +1313,8 @@ func (sr SpatialReference) SetGnomonic(
 func (sr SpatialReference) SetHOM(
 	centerLat, centerLong, azimuth, rectToSkew, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetHOM(
 		sr.cval,
 		C.double(centerLat),
@@ -1144,7 +1326,7 @@ func (sr SpatialReference) SetHOM(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1154,6 +1336,8 @@ func (sr SpatialReference) SetHOM(
 func (sr SpatialReference) SetHOM2PNO(
 	centerLat, lat1, long1, lat2, long2, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetHOM2PNO(
 		sr.cval,
 		C.double(centerLat),
@@ -1166,7 +1350,7 @@ func (sr SpatialReference) SetHOM2PNO(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1176,6 +1360,8 @@ func (sr SpatialReference) SetHOM2PNO(
 func (sr SpatialReference) SetIWMPolyconic(
 	lat1, lat2, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetIWMPolyconic(
 		sr.cval,
 		C.double(lat1),
@@ -1185,7 +1371,7 @@ func (sr SpatialReference) SetIWMPolyconic(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1195,6 +1381,8 @@ func (sr SpatialReference) SetIWMPolyconic(
 func (sr SpatialReference) SetKrovak(
 	centerLat, centerLong, azimuth, psuedoStdParallel, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetKrovak(
 		sr.cval,
 		C.double(centerLat),
@@ -1206,7 +1394,7 @@ func (sr SpatialReference) SetKrovak(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1216,6 +1404,8 @@ func (sr SpatialReference) SetKrovak(
 func (sr SpatialReference) SetLAEA(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetLAEA(
 		sr.cval,
 		C.double(centerLat),
@@ -1224,7 +1414,7 @@ func (sr SpatialReference) SetLAEA(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1234,6 +1424,8 @@ func (sr SpatialReference) SetLAEA(
 func (sr SpatialReference) SetLCC(
 	stdp1, stdp2, centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetLCC(
 		sr.cval,
 		C.double(stdp1),
@@ -1244,7 +1436,7 @@ func (sr SpatialReference) SetLCC(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1254,6 +1446,8 @@ func (sr SpatialReference) SetLCC(
 func (sr SpatialReference) SetLCC1SP(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetLCC1SP(
 		sr.cval,
 		C.double(centerLat),
@@ -1263,7 +1457,7 @@ func (sr SpatialReference) SetLCC1SP(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1273,6 +1467,8 @@ func (sr SpatialReference) SetLCC1SP(
 func (sr SpatialReference) SetLCCB(
 	stdp1, stdp2, centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetLCCB(
 		sr.cval,
 		C.double(stdp1),
@@ -1283,7 +1479,7 @@ func (sr SpatialReference) SetLCCB(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1293,6 +1489,8 @@ func (sr SpatialReference) SetLCCB(
 func (sr SpatialReference) SetMC(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetMC(
 		sr.cval,
 		C.double(centerLat),
@@ -1301,7 +1499,7 @@ func (sr SpatialReference) SetMC(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1311,6 +1509,8 @@ func (sr SpatialReference) SetMC(
 func (sr SpatialReference) SetMercator(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetMercator(
 		sr.cval,
 		C.double(centerLat),
@@ -1320,7 +1520,7 @@ func (sr SpatialReference) SetMercator(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1330,6 +1530,8 @@ func (sr SpatialReference) SetMercator(
 func (sr SpatialReference) SetMollweide(
 	centralMeridian, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetMollweide(
 		sr.cval,
 		C.double(centralMeridian),
@@ -1337,7 +1539,7 @@ func (sr SpatialReference) SetMollweide(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1347,6 +1549,8 @@ func (sr SpatialReference) SetMollweide(
 func (sr SpatialReference) SetNZMG(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetNZMG(
 		sr.cval,
 		C.double(centerLat),
@@ -1355,7 +1559,7 @@ func (sr SpatialReference) SetNZMG(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1365,6 +1569,8 @@ func (sr SpatialReference) SetNZMG(
 func (sr SpatialReference) SetOS(
 	originLat, meridian, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetOS(
 		sr.cval,
 		C.double(originLat),
@@ -1374,7 +1580,7 @@ func (sr SpatialReference) SetOS(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1384,6 +1590,8 @@ func (sr SpatialReference) SetOS(
 func (sr SpatialReference) SetOrthographic(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetOrthographic(
 		sr.cval,
 		C.double(centerLat),
@@ -1392,7 +1600,7 @@ func (sr SpatialReference) SetOrthographic(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1402,6 +1610,8 @@ func (sr SpatialReference) SetOrthographic(
 func (sr SpatialReference) SetPolyconic(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetPolyconic(
 		sr.cval,
 		C.double(centerLat),
@@ -1410,7 +1620,7 @@ func (sr SpatialReference) SetPolyconic(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1420,6 +1630,8 @@ func (sr SpatialReference) SetPolyconic(
 func (sr SpatialReference) SetPS(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetPS(
 		sr.cval,
 		C.double(centerLat),
@@ -1429,7 +1641,7 @@ func (sr SpatialReference) SetPS(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1439,6 +1651,8 @@ func (sr SpatialReference) SetPS(
 func (sr SpatialReference) SetRobinson(
 	centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetRobinson(
 		sr.cval,
 		C.double(centerLong),
@@ -1446,7 +1660,7 @@ func (sr SpatialReference) SetRobinson(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1456,6 +1670,8 @@ func (sr SpatialReference) SetRobinson(
 func (sr SpatialReference) SetSinusoidal(
 	centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetSinusoidal(
 		sr.cval,
 		C.double(centerLong),
@@ -1463,7 +1679,7 @@ func (sr SpatialReference) SetSinusoidal(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1473,6 +1689,8 @@ func (sr SpatialReference) SetSinusoidal(
 func (sr SpatialReference) SetStereographic(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetStereographic(
 		sr.cval,
 		C.double(centerLat),
@@ -1482,7 +1700,7 @@ func (sr SpatialReference) SetStereographic(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1492,6 +1710,8 @@ func (sr SpatialReference) SetStereographic(
 func (sr SpatialReference) SetSOC(
 	latitudeOfOrigin, centralMeridian, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetSOC(
 		sr.cval,
 		C.double(latitudeOfOrigin),
@@ -1500,7 +1720,7 @@ func (sr SpatialReference) SetSOC(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1510,6 +1730,8 @@ func (sr SpatialReference) SetSOC(
 func (sr SpatialReference) SetTM(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetTM(
 		sr.cval,
 		C.double(centerLat),
@@ -1519,7 +1741,7 @@ func (sr SpatialReference) SetTM(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1529,8 +1751,10 @@ func (sr SpatialReference) SetTM(
 func (sr SpatialReference) SetTMVariant(
 	variantName string, centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
 	cName := C.CString(variantName)
 	defer C.free(unsafe.Pointer(cName))
+	defer captureErrors()()
 	err := C.OSRSetTMVariant(
 		sr.cval,
 		cName,
@@ -1541,7 +1765,7 @@ func (sr SpatialReference) SetTMVariant(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1551,6 +1775,8 @@ func (sr SpatialReference) SetTMVariant(
 func (sr SpatialReference) SetTMG(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetTMG(
 		sr.cval,
 		C.double(centerLat),
@@ -1559,7 +1785,7 @@ func (sr SpatialReference) SetTMG(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1569,6 +1795,8 @@ func (sr SpatialReference) SetTMG(
 func (sr SpatialReference) SetTMSO(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetTMSO(
 		sr.cval,
 		C.double(centerLat),
@@ -1578,7 +1806,7 @@ func (sr SpatialReference) SetTMSO(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1588,6 +1816,8 @@ func (sr SpatialReference) SetTMSO(
 func (sr SpatialReference) SetVDG(
 	centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer runtime.KeepAlive(sr)
+	defer captureErrors()()
 	err := C.OSRSetVDG(
 		sr.cval,
 		C.double(centerLong),
@@ -1595,7 +1825,7 @@ func (sr SpatialReference) SetVDG(
 		C.double(falseNorthing),
 	)
 	if err != 0 {
-		return error(err)
+		return ogrError(err)
 	}
 
 	return nil
@@ -1611,36 +1841,145 @@ func CleanupSR() {
 /* -------------------------------------------------------------------- */
 
 type CoordinateTransform struct {
-	cval C.OGRCoordinateTransformationH
+	cval  C.OGRCoordinateTransformationH
+	owner *owner
 }
 
-// Create a new CoordinateTransform
+// Create a new CoordinateTransform.  The error tells why the two spatial
+// references cannot be related, as reported by GDAL.
 func CreateCoordinateTransform(
 	source SpatialReference,
 	dest SpatialReference,
-) CoordinateTransform {
+) (CoordinateTransform, error) {
+	defer runtime.KeepAlive(source)
+	defer runtime.KeepAlive(dest)
+	if source.cval == nil || dest.cval == nil {
+		return CoordinateTransform{}, newError(CPLE_ObjectNull, "Error: spatial reference is not valid")
+	}
+
+	defer captureErrors()()
 	ct := C.OCTNewCoordinateTransformation(source.cval, dest.cval)
-	return CoordinateTransform{ct}
+	if ct == nil {
+		return CoordinateTransform{}, lastError(CPLE_AppDefined, "Error: cannot create coordinate transform")
+	}
+	return CoordinateTransform{ct, own(func() { C.OCTDestroyCoordinateTransformation(ct) })}, nil
 }
 
-// Destroy CoordinateTransform
+// Destroy CoordinateTransform.  Destroying it twice does nothing.
 func (ct CoordinateTransform) Destroy() {
-	C.OCTDestroyCoordinateTransformation(ct.cval)
+	ct.owner.release()
+}
+
+// Transform points in place.  z may be nil for 2D points.  The returned
+// slice tells which points were transformed; the others are left as they
+// were or set to infinity, depending on the GDAL version.  An error is
+// returned only if no point could be transformed.
+func (ct CoordinateTransform) Transform(x, y, z []float64) ([]bool, error) {
+	defer runtime.KeepAlive(ct)
+	if ct.cval == nil {
+		return nil, newError(CPLE_ObjectNull, "Error: coordinate transform is not valid")
+	}
+	if len(y) != len(x) || (z != nil && len(z) != len(x)) {
+		return nil, newError(CPLE_IllegalArg, "Error: got %d x, %d y and %d z coordinates", len(x), len(y), len(z))
+	}
+	if len(x) == 0 {
+		return []bool{}, nil
+	}
+
+	var cZ *C.double
+	if z != nil {
+		cZ = (*C.double)(unsafe.Pointer(&z[0]))
+	}
+	success := make([]C.int, len(x))
+
+	defer captureErrors()()
+	ret := C.OCTTransformEx(
+		ct.cval,
+		C.int(len(x)),
+		(*C.double)(unsafe.Pointer(&x[0])),
+		(*C.double)(unsafe.Pointer(&y[0])),
+		cZ,
+		&success[0],
+	)
+
+	ok := make([]bool, len(x))
+	transformed := false
+	for i, s := range success {
+		ok[i] = s != 0
+		transformed = transformed || ok[i]
+	}
+	if ret == 0 && !transformed {
+		return ok, lastError(CPLE_AppDefined, "Error: failed to transform %d points", len(x))
+	}
+	return ok, nil
+}
+
+// Transform a single point
+func (ct CoordinateTransform) TransformPoint(x, y, z float64) (float64, float64, float64, error) {
+	xs, ys, zs := []float64{x}, []float64{y}, []float64{z}
+	if _, err := ct.Transform(xs, ys, zs); err != nil {
+		return x, y, z, err
+	}
+	return xs[0], ys[0], zs[0], nil
+}
+
+// Transform a bounding box, returning the box enclosing its transformed
+// edges.  Each edge is densified with densifyPoints intermediate points,
+// 21 being a common choice, so that curved edges are enclosed too.  Points
+// that cannot be transformed are ignored.  Boxes crossing the antimeridian
+// of the target are not detected.
+func (ct CoordinateTransform) TransformBounds(env Envelope, densifyPoints int) (Envelope, error) {
+	if densifyPoints < 0 {
+		return Envelope{}, newError(CPLE_IllegalArg, "Error: negative number of densify points %d", densifyPoints)
+	}
+
+	// walk the four edges, corners included once
+	segments := densifyPoints + 1
+	x := make([]float64, 0, 4*segments)
+	y := make([]float64, 0, 4*segments)
+	dx := (env.MaxX() - env.MinX()) / float64(segments)
+	dy := (env.MaxY() - env.MinY()) / float64(segments)
+	for i := 0; i < segments; i++ {
+		step := float64(i)
+		x = append(x, env.MinX()+step*dx, env.MaxX(), env.MaxX()-step*dx, env.MinX())
+		y = append(y, env.MinY(), env.MinY()+step*dy, env.MaxY(), env.MaxY()-step*dy)
+	}
+
+	ok, err := ct.Transform(x, y, nil)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	var bounds Envelope
+	first := true
+	for i := range x {
+		if !ok[i] || math.IsInf(x[i], 0) || math.IsInf(y[i], 0) {
+			continue
+		}
+		if first || x[i] < bounds.MinX() {
+			bounds.SetMinX(x[i])
+		}
+		if first || x[i] > bounds.MaxX() {
+			bounds.SetMaxX(x[i])
+		}
+		if first || y[i] < bounds.MinY() {
+			bounds.SetMinY(y[i])
+		}
+		if first || y[i] > bounds.MaxY() {
+			bounds.SetMaxY(y[i])
+		}
+		first = false
+	}
+	if first {
+		return Envelope{}, newError(CPLE_AppDefined, "Error: no point of the bounds could be transformed")
+	}
+	return bounds, nil
 }
 
 // Fetch list of possible projection methods
 func ProjectionMethods() []string {
 	p := C.OPTGetProjectionMethods()
-	var strings []string
-	q := uintptr(unsafe.Pointer(p))
-	for {
-		p = (**C.char)(unsafe.Pointer(q))
-		if *p == nil {
-			break
-		}
-		strings = append(strings, C.GoString(*p))
-		q += unsafe.Sizeof(q)
-	}
+	strings := goStringList(p)
 
 	return strings
 }
@@ -1656,16 +1995,7 @@ func ParameterList(method string) (params []string, name string) {
 
 	name = C.GoString(cName)
 
-	var strings []string
-	q := uintptr(unsafe.Pointer(p))
-	for {
-		p = (**C.char)(unsafe.Pointer(q))
-		if *p == nil {
-			break
-		}
-		strings = append(strings, C.GoString(*p))
-		q += unsafe.Sizeof(q)
-	}
+	strings := goStringList(p)
 
 	return strings, name
 }
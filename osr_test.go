@@ -0,0 +1,205 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gdal
+
+import (
+	"math"
+	"testing"
+)
+
+const (
+	wgs84Proj4       = "+proj=longlat +datum=WGS84 +no_defs"
+	webMercatorProj4 = "+proj=merc +a=6378137 +b=6378137 +lat_ts=0 +lon_0=0 +x_0=0 +y_0=0 +k=1 +units=m +nadgrids=@null +no_defs"
+	webMercatorMaxX  = 20037508.342789244
+)
+
+// Create a transform between two PROJ.4 definitions, which keep the
+// longitude first whatever the GDAL version
+func createProj4Transform(t *testing.T, source, dest string) CoordinateTransform {
+	srcSRS := CreateSpatialReference("")
+	defer srcSRS.Destroy()
+	if err := srcSRS.FromProj4(source); err != nil {
+		t.Fatal(err)
+	}
+	dstSRS := CreateSpatialReference("")
+	defer dstSRS.Destroy()
+	if err := dstSRS.FromProj4(dest); err != nil {
+		t.Fatal(err)
+	}
+	ct, err := CreateCoordinateTransform(srcSRS, dstSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ct
+}
+
+func TestCoordinateTransform(t *testing.T) {
+	ct := createProj4Transform(t, wgs84Proj4, webMercatorProj4)
+	defer ct.Destroy()
+
+	x, y, z, err := ct.TransformPoint(180, 0, 0)
+	if err != nil || math.Abs(x-webMercatorMaxX) > 1e-3 || math.Abs(y) > 1e-3 || z != 0 {
+		t.Errorf("TransformPoint: got %v, %v, %v, %v", x, y, z, err)
+	}
+
+	xs := []float64{0, -180, 10}
+	ys := []float64{0, 0, 90}
+	ok, err := ct.Transform(xs, ys, nil)
+	if err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+	if !ok[0] || !ok[1] || ok[2] {
+		t.Errorf("Transform: got success %v, want [true true false]", ok)
+	}
+	if math.Abs(xs[0]) > 1e-3 || math.Abs(xs[1]+webMercatorMaxX) > 1e-3 {
+		t.Errorf("Transform: got x %v", xs)
+	}
+
+	if _, err := ct.Transform([]float64{0}, nil, nil); err == nil {
+		t.Error("Transform with mismatched slices: got no error")
+	}
+	if _, err := ct.Transform([]float64{10}, []float64{90}, nil); err == nil {
+		t.Error("Transform of the pole: got no error")
+	}
+	if _, err := (CoordinateTransform{}).Transform([]float64{0}, []float64{0}, nil); err == nil {
+		t.Error("Transform with invalid transform: got no error")
+	}
+}
+
+func TestTransformBounds(t *testing.T) {
+	ct := createProj4Transform(t, webMercatorProj4, wgs84Proj4)
+	defer ct.Destroy()
+
+	var env Envelope
+	env.SetMinX(-webMercatorMaxX)
+	env.SetMaxX(webMercatorMaxX)
+	env.SetMinY(0)
+	env.SetMaxY(webMercatorMaxX)
+	bounds, err := ct.TransformBounds(env, 21)
+	if err != nil {
+		t.Fatalf("TransformBounds: %v", err)
+	}
+	want := [4]float64{-180, 180, 0, 85.0511287798}
+	got := [4]float64{bounds.MinX(), bounds.MaxX(), bounds.MinY(), bounds.MaxY()}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-6 {
+			t.Errorf("TransformBounds: got %v, want %v", got, want)
+			break
+		}
+	}
+
+	// a box of a conic projection bulges beyond its corners once densified
+	lcc := "+proj=lcc +lat_1=30 +lat_2=60 +lat_0=45 +lon_0=0 +datum=WGS84 +units=m +no_defs"
+	ct = createProj4Transform(t, lcc, wgs84Proj4)
+	defer ct.Destroy()
+	env.SetMinX(-2e6)
+	env.SetMaxX(2e6)
+	env.SetMinY(-1e6)
+	env.SetMaxY(1e6)
+	corners, err := ct.TransformBounds(env, 0)
+	if err != nil {
+		t.Fatalf("TransformBounds without densification: %v", err)
+	}
+	dense, err := ct.TransformBounds(env, 21)
+	if err != nil {
+		t.Fatalf("TransformBounds: %v", err)
+	}
+	if dense.MaxY() <= corners.MaxY() {
+		t.Errorf("TransformBounds: densified max y %v not above corners %v", dense.MaxY(), corners.MaxY())
+	}
+}
+
+func TestCoordinateTransformErrors(t *testing.T) {
+	empty := CreateSpatialReference("")
+	defer empty.Destroy()
+	wgs84 := CreateSpatialReference("")
+	defer wgs84.Destroy()
+	if err := wgs84.FromProj4(wgs84Proj4); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CreateCoordinateTransform(empty, wgs84); err == nil {
+		t.Error("CreateCoordinateTransform from empty spatial reference: got no error")
+	}
+	if _, err := CreateCoordinateTransform(SpatialReference{}, wgs84); err == nil {
+		t.Error("CreateCoordinateTransform from invalid spatial reference: got no error")
+	}
+
+	geom, err := CreateFromWKT("POINT (1 2)", SpatialReference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer geom.Destroy()
+	if err := geom.Transform(CoordinateTransform{}); err == nil {
+		t.Error("Transform with invalid transform: got no error")
+	}
+	if err := geom.TransformTo(wgs84); err == nil {
+		t.Error("TransformTo of geometry without spatial reference: got no error")
+	}
+	if err := geom.TransformTo(SpatialReference{}); err == nil {
+		t.Error("TransformTo invalid spatial reference: got no error")
+	}
+	if err := (Geometry{}).TransformTo(wgs84); err == nil {
+		t.Error("TransformTo of invalid geometry: got no error")
+	}
+
+	mercator := CreateSpatialReference("")
+	defer mercator.Destroy()
+	if err := mercator.FromProj4(webMercatorProj4); err != nil {
+		t.Fatal(err)
+	}
+	geom.SetSpatialReference(wgs84)
+	if err := geom.TransformTo(mercator); err != nil {
+		t.Errorf("TransformTo: %v", err)
+	}
+}
+
+func TestSpatialReferenceRelease(t *testing.T) {
+	createSRS := func() SpatialReference {
+		srs := CreateSpatialReference("")
+		if err := srs.FromProj4(wgs84Proj4); err != nil {
+			t.Fatal(err)
+		}
+		return srs
+	}
+
+	// releasing an owned handle more than once frees it once
+	srs := createSRS()
+	srs.Release()
+	if srs.owner.owned() {
+		t.Error("Release: handle still owns its reference")
+	}
+	srs.Release()
+	srs.Destroy()
+
+	srs = createSRS()
+	srs.Destroy()
+	srs.Release()
+
+	// references taken with Reference are dropped before the owned one
+	srs = createSRS()
+	defer srs.Destroy()
+	if count := srs.Reference(); count != 2 {
+		t.Fatalf("Reference: got count %d, want 2", count)
+	}
+	srs.Release()
+	if !srs.owner.owned() {
+		t.Error("Release: dropped the owned reference before the one taken")
+	}
+	if proj4, err := srs.ToProj4(); err != nil || proj4 == "" {
+		t.Errorf("spatial reference released too early: %q, %v", proj4, err)
+	}
+
+	// a reference taken on a borrowed handle is dropped by Release, which
+	// does nothing more
+	borrowed := SpatialReference{srs.cval, borrow(srs.owner)}
+	count := borrowed.Reference()
+	borrowed.Release()
+	borrowed.Release()
+	if got := borrowed.Reference(); got != count {
+		t.Errorf("Reference after Release: got count %d, want %d", got, count)
+	}
+	borrowed.Release()
+}
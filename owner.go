@@ -0,0 +1,111 @@
+package gdal
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Handle ownership.                                               */
+/* -------------------------------------------------------------------- */
+
+var finalizers atomic.Bool
+
+// Enable or disable runtime finalizers for handles created from now on.
+// With finalizers enabled, an owned C object is released by the garbage
+// collector once all copies of its handle became unreachable, so a missing
+// Close or Destroy no longer leaks it.  The methods of the package keep
+// their handles reachable while GDAL uses them.  Explicit release is still
+// advised, as finalizers run at an unspecified time.
+func SetFinalizers(enabled bool) {
+	finalizers.Store(enabled)
+}
+
+// Ownership record shared by all copies of a handle, so that releasing the
+// C object through any of them releases it exactly once.  Handles of
+// borrowed objects, such as the geometry of a feature, never release them
+// and keep the owner of their parent reachable instead.
+type owner struct {
+	mu   sync.Mutex
+	free func()
+	// owner of the object a borrowed object belongs to
+	parent *owner
+	// references taken on a reference counted object through the handle
+	refs int
+}
+
+// Track a C object owned by the caller, to be released by free
+func own(free func()) *owner {
+	o := &owner{free: free}
+	if finalizers.Load() {
+		runtime.SetFinalizer(o, (*owner).release)
+	}
+	return o
+}
+
+// Track a C object owned by the object behind parent
+func borrow(parent *owner) *owner {
+	return &owner{parent: parent}
+}
+
+// Release the C object, unless it is borrowed or was already released
+func (o *owner) release() {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	free := o.free
+	o.free = nil
+	o.mu.Unlock()
+
+	if free != nil {
+		runtime.SetFinalizer(o, nil)
+		free()
+	}
+}
+
+// Give up ownership without releasing the C object, after GDAL took it over
+func (o *owner) disown() {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	o.free = nil
+	o.mu.Unlock()
+	runtime.SetFinalizer(o, nil)
+}
+
+// Report whether the handle owns a C object that has not been released yet
+func (o *owner) owned() bool {
+	if o == nil {
+		return false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.free != nil
+}
+
+// Record a reference taken on the C object through the handle
+func (o *owner) addRef() {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	o.refs++
+	o.mu.Unlock()
+}
+
+// Forget a reference recorded by addRef, reporting whether there was one
+func (o *owner) dropRef() bool {
+	if o == nil {
+		return false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.refs == 0 {
+		return false
+	}
+	o.refs--
+	return true
+}
@@ -0,0 +1,169 @@
+package gdal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Composing progress callbacks.                                   */
+/* -------------------------------------------------------------------- */
+
+// Split the progress of an operation made of consecutive steps, such as a
+// warp followed by overviews and a copy, into one callback per step.  Each
+// step gets a share of the range proportional to its weight.
+func SplitProgress(progress ProgressFunc, data interface{}, weights ...float64) []ProgressFunc {
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+
+	steps := make([]ProgressFunc, len(weights))
+	done := 0.0
+	for i, weight := range weights {
+		min, max := 0.0, 1.0
+		if total > 0 {
+			min, max = done/total, (done+weight)/total
+		}
+		steps[i] = CreateScaledProgress(min, max, progress, data)
+		done += weight
+	}
+	return steps
+}
+
+// Create a progress callback that interrupts the operation once ctx is
+// done, and reports to progress, which may be nil, until then
+func ContextProgress(ctx context.Context, progress ProgressFunc, data interface{}) ProgressFunc {
+	return func(complete float64, message string, _ interface{}) int {
+		if ctx.Err() != nil {
+			return 0
+		}
+		if progress == nil {
+			return 1
+		}
+		return progress(complete, message, data)
+	}
+}
+
+// Run the operation behind a Context variant, passing it a progress
+// callback that interrupts it once ctx is done.  An operation interrupted
+// this way, or not started because ctx was done already, fails with an
+// *InterruptedError.
+func runContext(
+	ctx context.Context,
+	progress ProgressFunc,
+	data interface{},
+	operation func(progress ProgressFunc) error,
+) error {
+	if err := ctx.Err(); err != nil {
+		return &InterruptedError{0, err, nil}
+	}
+
+	var mu sync.Mutex
+	reached := 0.0
+	track := func(complete float64, message string, _ interface{}) int {
+		mu.Lock()
+		reached = complete
+		mu.Unlock()
+		if progress == nil {
+			return 1
+		}
+		return progress(complete, message, data)
+	}
+
+	err := operation(ContextProgress(ctx, track, nil))
+	if err != nil && ctx.Err() != nil {
+		mu.Lock()
+		defer mu.Unlock()
+		return &InterruptedError{reached, ctx.Err(), err}
+	}
+	return err
+}
+
+/* -------------------------------------------------------------------- */
+/*      Progress adapters.                                              */
+/* -------------------------------------------------------------------- */
+
+// Create a progress callback printing "0...10...20..." to w, as
+// TermProgress does on stdout
+func TerminalProgress(w io.Writer) ProgressFunc {
+	var mu sync.Mutex
+	lastTick := -1
+	return func(complete float64, message string, _ interface{}) int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// one tick every 2.5%, a number every fourth tick
+		tick := int(complete * 40)
+		if tick < 0 {
+			tick = 0
+		}
+		if tick > 40 {
+			tick = 40
+		}
+		// a new operation started after the previous one completed
+		if tick < lastTick && lastTick >= 39 {
+			lastTick = -1
+		}
+		if tick <= lastTick {
+			return 1
+		}
+
+		for lastTick < tick {
+			lastTick++
+			if lastTick%4 == 0 {
+				fmt.Fprintf(w, "%d", lastTick/4*10)
+			} else {
+				fmt.Fprint(w, ".")
+			}
+		}
+		if tick == 40 {
+			fmt.Fprint(w, " - done.\n")
+		}
+		return 1
+	}
+}
+
+// Progress of an operation, as sent by ChannelProgress
+type ProgressUpdate struct {
+	Complete float64
+	Message  string
+}
+
+// Create a progress callback sending updates to ch.  Updates are dropped
+// while ch is full, so that a slow reader never stalls the operation.
+func ChannelProgress(ch chan<- ProgressUpdate) ProgressFunc {
+	return func(complete float64, message string, _ interface{}) int {
+		select {
+		case ch <- ProgressUpdate{complete, message}:
+		default:
+		}
+		return 1
+	}
+}
+
+// Create a progress callback logging at info level each time the operation
+// advanced by at least step, and when it completes
+func LoggerProgress(logger *slog.Logger, step float64) ProgressFunc {
+	var mu sync.Mutex
+	last := -1.0
+	return func(complete float64, message string, _ interface{}) int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if complete < last {
+			// a new operation started
+			last = -1
+		}
+		finished := complete >= 1 && last < 1
+		if last >= 0 && complete-last < step && !finished {
+			return 1
+		}
+		last = complete
+		logger.Info("progress", "complete", complete, "message", message)
+		return 1
+	}
+}
@@ -0,0 +1,164 @@
+package gdal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+)
+
+// Run a fake operation reporting progress in steps of 1/n
+func runProgress(progress ProgressFunc, n int) bool {
+	for i := 0; i <= n; i++ {
+		if progress(float64(i)/float64(n), "", nil) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestScaledProgress(t *testing.T) {
+	var reported []float64
+	record := func(complete float64, message string, data interface{}) int {
+		if data != "job" {
+			t.Errorf("data: got %v, want job", data)
+		}
+		reported = append(reported, complete)
+		return 1
+	}
+
+	steps := SplitProgress(record, "job", 2, 1, 1)
+	// the first step itself has two halves
+	warp := CreateScaledProgress(0.5, 1, steps[0], nil)
+	runProgress(warp, 2)
+	runProgress(steps[1], 1)
+	ScaledProgress(1, "", steps[2])
+
+	want := []float64{0.25, 0.375, 0.5, 0.5, 0.75, 1}
+	if len(reported) != len(want) {
+		t.Fatalf("got %v, want %v", reported, want)
+	}
+	for i := range want {
+		if math.Abs(reported[i]-want[i]) > 1e-12 {
+			t.Fatalf("got %v, want %v", reported, want)
+		}
+	}
+}
+
+func TestContextProgress(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	progress := ContextProgress(ctx, nil, nil)
+	if !runProgress(progress, 4) {
+		t.Error("interrupted before cancel")
+	}
+	cancel()
+	if runProgress(progress, 4) {
+		t.Error("not interrupted after cancel")
+	}
+}
+
+func TestProgressAdapters(t *testing.T) {
+	var out bytes.Buffer
+	runProgress(TerminalProgress(&out), 10)
+	want := "0...10...20...30...40...50...60...70...80...90...100 - done.\n"
+	if out.String() != want {
+		t.Errorf("TerminalProgress: got %q, want %q", out.String(), want)
+	}
+
+	ch := make(chan ProgressUpdate, 2)
+	runProgress(ChannelProgress(ch), 4)
+	if update := <-ch; update.Complete != 0 {
+		t.Errorf("ChannelProgress: got %v first", update)
+	}
+	if len(ch) != 1 {
+		t.Errorf("ChannelProgress: %d updates queued, want 1", len(ch))
+	}
+
+	var log bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&log, nil))
+	runProgress(LoggerProgress(logger, 0.5), 10)
+	if lines := strings.Count(log.String(), "\n"); lines != 3 {
+		t.Errorf("LoggerProgress: got %d lines, want 3:\n%s", lines, log.String())
+	}
+}
+
+func TestRunContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	failure := newError(CPLE_UserInterrupt, "User terminated")
+	err := runContext(ctx, nil, nil, func(progress ProgressFunc) error {
+		progress(0.25, "", nil)
+		cancel()
+		if progress(0.5, "", nil) != 0 {
+			t.Error("not interrupted after cancel")
+		}
+		return failure
+	})
+
+	var interrupted *InterruptedError
+	if !errors.As(err, &interrupted) {
+		t.Fatalf("got %v, want *InterruptedError", err)
+	}
+	if interrupted.Complete != 0.25 {
+		t.Errorf("Complete: got %v, want 0.25", interrupted.Complete)
+	}
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, CPLE_UserInterrupt) {
+		t.Errorf("%v does not match context.Canceled and CPLE_UserInterrupt", err)
+	}
+
+	// a done context prevents the operation from starting
+	started := false
+	err = runContext(ctx, nil, nil, func(ProgressFunc) error {
+		started = true
+		return nil
+	})
+	if started || !errors.Is(err, context.Canceled) {
+		t.Errorf("started %v with done context, got %v", started, err)
+	}
+}
+
+func TestCopyWholeRasterContext(t *testing.T) {
+	src := createMemoryRaster(t, 4, 4, Byte)
+	defer src.Close()
+	dst := createMemoryRaster(t, 4, 4, Byte)
+	defer dst.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := src.CopyWholeRasterContext(ctx, dst, nil, nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
+
+// Cancel the context from the progress callback of a reprojection split in
+// many chunks, so that GDAL itself stops partway through
+func TestReprojectImageContextCancel(t *testing.T) {
+	src := createSquareRaster(t, Byte)
+	defer src.Close()
+	dst := createSquareRaster(t, Byte)
+	defer dst.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	progress := func(complete float64, message string, data interface{}) int {
+		if complete > 0.2 {
+			cancel()
+		}
+		return 1
+	}
+	err := src.ReprojectImageContext(ctx, "", dst, "", GRA_NearestNeighbour, 1024, 0, progress, nil, nil)
+
+	var interrupted *InterruptedError
+	if !errors.As(err, &interrupted) {
+		t.Fatalf("got %v, want *InterruptedError", err)
+	}
+	if interrupted.Complete <= 0 || interrupted.Complete >= 1 {
+		t.Errorf("Complete: got %v, want between 0 and 1", interrupted.Complete)
+	}
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, CPLE_UserInterrupt) {
+		t.Errorf("%v does not match context.Canceled and CPLE_UserInterrupt", err)
+	}
+}
@@ -0,0 +1,227 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Pixel types.                                                    */
+/* -------------------------------------------------------------------- */
+
+// Pixel of a CInt16 raster
+type ComplexInt16 struct {
+	Real, Imag int16
+}
+
+// Pixel of a CInt32 raster
+type ComplexInt32 struct {
+	Real, Imag int32
+}
+
+// Go types holding the pixels of a raster, one for each DataType
+type Pixel interface {
+	uint8 | int8 | uint16 | int16 | uint32 | int32 | uint64 | int64 |
+		float32 | float64 | ComplexInt16 | ComplexInt32 | complex64 | complex128
+}
+
+// Return the data type of pixels of type T, or Unknown if the linked GDAL
+// does not support it
+func DataTypeOf[T Pixel]() DataType {
+	var pixel T
+	switch any(pixel).(type) {
+	case uint8:
+		return Byte
+	case int8:
+		return Int8
+	case uint16:
+		return UInt16
+	case int16:
+		return Int16
+	case uint32:
+		return UInt32
+	case int32:
+		return Int32
+	case uint64:
+		return UInt64
+	case int64:
+		return Int64
+	case float32:
+		return Float32
+	case float64:
+		return Float64
+	case ComplexInt16:
+		return CInt16
+	case ComplexInt32:
+		return CInt32
+	case complex64:
+		return CFloat32
+	case complex128:
+		return CFloat64
+	}
+	return Unknown
+}
+
+/* -------------------------------------------------------------------- */
+/*      Typed raster I/O.                                               */
+/* -------------------------------------------------------------------- */
+
+// Read a window of the band into a newly allocated buffer
+func ReadWindow[T Pixel](band RasterBand, xOff, yOff, xSize, ySize int) ([]T, error) {
+	return ReadWindowResampled[T](band, xOff, yOff, xSize, ySize, xSize, ySize)
+}
+
+// Read a window of the band into a newly allocated buffer of bufXSize x
+// bufYSize pixels, resampling it if the sizes differ
+func ReadWindowResampled[T Pixel](
+	band RasterBand,
+	xOff, yOff, xSize, ySize int,
+	bufXSize, bufYSize int,
+) ([]T, error) {
+	if bufXSize <= 0 || bufYSize <= 0 {
+		return nil, newError(CPLE_IllegalArg, "Error: invalid buffer size %dx%d", bufXSize, bufYSize)
+	}
+	data := make([]T, bufXSize*bufYSize)
+	err := band.IO(Read, xOff, yOff, xSize, ySize, data, bufXSize, bufYSize, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Write a buffer of xSize x ySize pixels to a window of the band
+func WriteWindow[T Pixel](band RasterBand, xOff, yOff, xSize, ySize int, data []T) error {
+	return WriteWindowResampled(band, xOff, yOff, xSize, ySize, data, xSize, ySize)
+}
+
+// Write a buffer of bufXSize x bufYSize pixels to a window of the band,
+// resampling it if the sizes differ
+func WriteWindowResampled[T Pixel](
+	band RasterBand,
+	xOff, yOff, xSize, ySize int,
+	data []T,
+	bufXSize, bufYSize int,
+) error {
+	return band.IO(Write, xOff, yOff, xSize, ySize, data, bufXSize, bufYSize, 0, 0)
+}
+
+/* -------------------------------------------------------------------- */
+/*      Helper functions.                                               */
+/* -------------------------------------------------------------------- */
+
+// Fetch the data type, address and length of a slice of pixels
+func pixelBuffer(buffer interface{}) (DataType, unsafe.Pointer, int, error) {
+	switch data := buffer.(type) {
+	case []uint8:
+		return sliceBuffer(data)
+	case []int8:
+		return sliceBuffer(data)
+	case []uint16:
+		return sliceBuffer(data)
+	case []int16:
+		return sliceBuffer(data)
+	case []uint32:
+		return sliceBuffer(data)
+	case []int32:
+		return sliceBuffer(data)
+	case []uint64:
+		return sliceBuffer(data)
+	case []int64:
+		return sliceBuffer(data)
+	case []float32:
+		return sliceBuffer(data)
+	case []float64:
+		return sliceBuffer(data)
+	case []ComplexInt16:
+		return sliceBuffer(data)
+	case []ComplexInt32:
+		return sliceBuffer(data)
+	case []complex64:
+		return sliceBuffer(data)
+	case []complex128:
+		return sliceBuffer(data)
+	}
+	return Unknown, nil, 0, newError(CPLE_IllegalArg, "Error: buffer is not a valid data type (must be a valid numeric slice)")
+}
+
+// Fetch the data type, address and length of a typed slice of pixels
+func sliceBuffer[T Pixel](data []T) (DataType, unsafe.Pointer, int, error) {
+	dataType := DataTypeOf[T]()
+	if dataType == Unknown {
+		return Unknown, nil, 0, newError(CPLE_NotSupported, "Error: %T pixels are not supported by GDAL %s", data, RELEASE_NAME)
+	}
+	if len(data) == 0 {
+		return dataType, nil, 0, nil
+	}
+	return dataType, unsafe.Pointer(&data[0]), len(data), nil
+}
+
+// Check that a raster I/O window lies within the raster, and that a buffer
+// of length pixels is large enough for the requested layout
+func checkIO(
+	xOff, yOff, xSize, ySize int,
+	rasterXSize, rasterYSize int,
+	dataType DataType,
+	length int,
+	bufXSize, bufYSize int,
+	bandCount int,
+	pixelSpace, lineSpace, bandSpace int,
+) error {
+	if xOff < 0 || yOff < 0 || xSize <= 0 || ySize <= 0 ||
+		xOff+xSize > rasterXSize || yOff+ySize > rasterYSize {
+		return newError(
+			CPLE_IllegalArg,
+			"Error: window %dx%d at (%d, %d) is outside of the %dx%d raster",
+			xSize, ySize, xOff, yOff, rasterXSize, rasterYSize,
+		)
+	}
+	if bufXSize <= 0 || bufYSize <= 0 {
+		return newError(CPLE_IllegalArg, "Error: invalid buffer size %dx%d", bufXSize, bufYSize)
+	}
+	if bandCount <= 0 {
+		return newError(CPLE_IllegalArg, "Error: invalid band count %d", bandCount)
+	}
+	if pixelSpace < 0 || lineSpace < 0 || bandSpace < 0 {
+		// negative spacings address memory before the buffer start
+		return newError(
+			CPLE_IllegalArg,
+			"Error: negative spacings %d, %d and %d are not supported",
+			pixelSpace, lineSpace, bandSpace,
+		)
+	}
+
+	pixelSize := dataType.Size() / 8
+	if pixelSpace == 0 {
+		pixelSpace = pixelSize
+	}
+	if lineSpace == 0 {
+		lineSpace = pixelSpace * bufXSize
+	}
+	if bandSpace == 0 {
+		bandSpace = lineSpace * bufYSize
+	}
+	needed := (bandCount-1)*bandSpace + (bufYSize-1)*lineSpace + (bufXSize-1)*pixelSpace + pixelSize
+	if length*pixelSize < needed {
+		return newError(
+			CPLE_IllegalArg,
+			"Error: buffer of %d bytes is too small, %d bytes needed",
+			length*pixelSize, needed,
+		)
+	}
+	return nil
+}
+
+// Convert a band map to C integers, returning nil for an empty map
+func cBandMap(bandMap []int) *C.int {
+	if len(bandMap) == 0 {
+		return nil
+	}
+	cBands := make([]C.int, len(bandMap))
+	for i, band := range bandMap {
+		cBands[i] = C.int(band)
+	}
+	return &cBands[0]
+}
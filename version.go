@@ -0,0 +1,176 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+*/
+import "C"
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Runtime version information.                                    */
+/* -------------------------------------------------------------------- */
+
+// Fetch version information of the GDAL library linked at run time.
+// Request is one of "VERSION_NUM", "RELEASE_DATE", "RELEASE_NAME",
+// "--version", "LICENSE" or, with GDAL 2.3 or later, "BUILD_INFO".
+func VersionInfo(request string) string {
+	cRequest := C.CString(request)
+	defer C.free(unsafe.Pointer(cRequest))
+	info := C.GDALVersionInfo(cRequest)
+	return C.GoString(info)
+}
+
+// Fetch the version number of the GDAL library linked at run time, to be
+// compared with VERSION_NUM
+func RuntimeVersionNum() int {
+	num, _ := strconv.Atoi(VersionInfo("VERSION_NUM"))
+	return num
+}
+
+// Fetch the release name of the GDAL library linked at run time, such as
+// "3.8.4"
+func RuntimeReleaseName() string {
+	return VersionInfo("RELEASE_NAME")
+}
+
+// Report whether the GDAL library linked at run time is compatible with
+// the given major and minor version
+func CheckVersion(major, minor int) bool {
+	defer captureErrors()()
+	ok := C.GDALCheckVersion(C.int(major), C.int(minor), nil)
+	return ok != 0
+}
+
+// Fetch the options GDAL was built with, such as "GEOS_ENABLED" or
+// "PROJ_BUILD_VERSION".  The map is empty before GDAL 2.3.
+func BuildInfo() map[string]string {
+	info := make(map[string]string)
+	for _, line := range strings.Split(VersionInfo("BUILD_INFO"), "\n") {
+		if name, value, ok := strings.Cut(line, "="); ok {
+			info[name] = value
+		}
+	}
+	return info
+}
+
+// Fetch the short names of all registered raster and vector drivers
+func DriverNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for i := 0; i < GetDriverCount(); i++ {
+		name := GetDriver(i).ShortName()
+		names = append(names, name)
+		seen[name] = true
+	}
+	// GDAL 1.x keeps vector drivers apart
+	for i := 0; i < OGRDriverCount(); i++ {
+		name := OGRDriverByIndex(i).Name()
+		if !seen[name] {
+			names = append(names, name)
+			seen[name] = true
+		}
+	}
+	return names
+}
+
+/* -------------------------------------------------------------------- */
+/*      Optional features.                                              */
+/* -------------------------------------------------------------------- */
+
+// Optional component of a GDAL build
+type BuildFeature string
+
+const (
+	// Geometry operations such as Buffer, Union or Intersection
+	FEATURE_GEOS = BuildFeature("GEOS")
+	// Coordinate transformations between spatial references
+	FEATURE_PROJ = BuildFeature("PROJ")
+	// SQLite and GeoPackage drivers
+	FEATURE_SQLITE = BuildFeature("SQLite")
+	// Network access through /vsicurl/ and the HTTP drivers
+	FEATURE_CURL = BuildFeature("CURL")
+)
+
+var (
+	featuresOnce sync.Once
+	features     map[BuildFeature]bool
+)
+
+// Report whether the GDAL library linked at run time provides the feature.
+// The answer is taken from the build information when GDAL reports it, and
+// from probing the library otherwise.
+func Supports(feature BuildFeature) bool {
+	featuresOnce.Do(detectFeatures)
+	return features[feature]
+}
+
+// Fill the feature table from the build information, or by probing
+func detectFeatures() {
+	info := BuildInfo()
+	features = make(map[BuildFeature]bool)
+
+	if enabled, ok := info["GEOS_ENABLED"]; ok {
+		features[FEATURE_GEOS] = enabled == "YES"
+	} else {
+		features[FEATURE_GEOS] = probeGEOS()
+	}
+
+	if _, ok := info["PROJ_BUILD_VERSION"]; ok {
+		features[FEATURE_PROJ] = true
+	} else {
+		features[FEATURE_PROJ] = probePROJ()
+	}
+
+	_, err := GetDriverByName("SQLite")
+	features[FEATURE_SQLITE] = err == nil || OGRDriverByName("SQLite").cval != nil
+
+	if enabled, ok := info["CURL_ENABLED"]; ok {
+		features[FEATURE_CURL] = enabled == "YES"
+	} else {
+		_, err := GetDriverByName("HTTP")
+		features[FEATURE_CURL] = err == nil || OGRDriverByName("HTTP").cval != nil
+	}
+}
+
+// Buffering a point fails without GEOS
+func probeGEOS() bool {
+	defer captureErrors()()
+	point := C.OGR_G_CreateGeometry(C.wkbPoint)
+	defer C.OGR_G_DestroyGeometry(point)
+	C.OGR_G_SetPoint_2D(point, 0, 0, 0)
+
+	buffer := C.OGR_G_Buffer(point, 1, 1)
+	if buffer == nil {
+		return false
+	}
+	C.OGR_G_DestroyGeometry(buffer)
+	return true
+}
+
+// Coordinate transformations cannot be created without PROJ
+func probePROJ() bool {
+	defer captureErrors()()
+	wgs84 := C.OSRNewSpatialReference(nil)
+	defer C.OSRDestroySpatialReference(wgs84)
+	mercator := C.OSRNewSpatialReference(nil)
+	defer C.OSRDestroySpatialReference(mercator)
+	cName := C.CString("WGS84")
+	defer C.free(unsafe.Pointer(cName))
+	if C.OSRSetWellKnownGeogCS(wgs84, cName) != C.OGRERR_NONE ||
+		C.OSRSetWellKnownGeogCS(mercator, cName) != C.OGRERR_NONE ||
+		C.OSRSetMercator(mercator, 0, 0, 1, 0, 0) != C.OGRERR_NONE {
+		return false
+	}
+
+	ct := C.OCTNewCoordinateTransformation(wgs84, mercator)
+	if ct == nil {
+		return false
+	}
+	C.OCTDestroyCoordinateTransformation(ct)
+	return true
+}
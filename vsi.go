@@ -1,22 +1,30 @@
 package gdal
 
 /*
-#include <cpl_vsi.h>
-//#include "go_gdal.h"
-//#include "gdal_version.h"
-
-#cgo linux  CFLAGS: -I/usr/include/gdal
-#cgo linux  LDFLAGS: -lgdal
-#cgo darwin pkg-config: gdal
-#cgo windows LDFLAGS: -lgdal.dll
+#include "go_gdal.h"
 */
 import "C"
 import (
+	"io/fs"
+	"path"
 	"unsafe"
 )
 
 type VSILFile *C.VSILFILE
-type VSIStatBufL C.VSIStatBufL
+
+// Information requested from VSIStatExL
+type VSIStatFlags int
+
+const (
+	// Check whether the object exists
+	VSI_STAT_EXISTS_FLAG = VSIStatFlags(C.VSI_STAT_EXISTS_FLAG)
+	// Fetch whether the object is a file or a directory
+	VSI_STAT_NATURE_FLAG = VSIStatFlags(C.VSI_STAT_NATURE_FLAG)
+	// Fetch the size of the object
+	VSI_STAT_SIZE_FLAG = VSIStatFlags(C.VSI_STAT_SIZE_FLAG)
+	// Report the reason of a failure as a VSI error, since GDAL 2.2
+	VSI_STAT_SET_ERROR_FLAG = VSIStatFlags(C.VSI_STAT_SET_ERROR_FLAG)
+)
 
 // Open file.
 //
@@ -215,7 +223,7 @@ func VSIFPutcL(c int, fp VSILFile) bool {
 
 // Get filesystem object info.
 //
-// Fetches status information about a filesystem object (file, directory, etc). The returned information is placed in the VSIStatBufL structure. For portability only the st_size (size in bytes), and st_mode (file type). This method is similar to VSIStat(), but will work on large files on systems where this requires special calls.
+// Fetches status information about a filesystem object (file, directory, etc). For portability only the size, modification time and type of the object are returned. This method is similar to VSIStat(), but will work on large files on systems where this requires special calls.
 //
 // This method goes through the VSIFileHandler virtualization and may work on unusual filesystems such as in memory.
 //
@@ -225,21 +233,15 @@ func VSIFPutcL(c int, fp VSILFile) bool {
 //	filename 	the path of the filesystem object to be queried. UTF-8 encoded.
 //
 // Returns:
-//	statBuf 	the structure with information.
-//	success 	True on success or false on an error.
-func VSIStatL(filename string) (statBuf VSIStatBufL, success bool) {
-	pszFilename := C.CString(filename)
-	defer C.free(unsafe.Pointer(pszFilename))
-
-	err := C.VSIStatL(pszFilename, (*C.VSIStatBufL)(&statBuf))
-	success = err == 0
-
-	return statBuf, success
+//	info 	the information about the object, named after the last element of filename.
+//	err 	a *fs.PathError on failure, matching fs.ErrNotExist with errors.Is if the object does not exist.
+func VSIStatL(filename string) (info fs.FileInfo, err error) {
+	return VSIStatExL(filename, 0)
 }
 
 // Get filesystem object info.
 //
-// Fetches status information about a filesystem object (file, directory, etc). The returned information is placed in the VSIStatBufL structure. For portability only the st_size (size in bytes), and st_mode (file type). This method is similar to VSIStat(), but will work on large files on systems where this requires special calls.
+// Fetches status information about a filesystem object (file, directory, etc). For portability only the size, modification time and type of the object are returned. This method is similar to VSIStat(), but will work on large files on systems where this requires special calls.
 //
 // This method goes through the VSIFileHandler virtualization and may work on unusual filesystems such as in memory.
 //
@@ -247,19 +249,17 @@ func VSIStatL(filename string) (statBuf VSIStatBufL, success bool) {
 //
 // Parameters:
 //	filename 	the path of the filesystem object to be queried. UTF-8 encoded.
-//	flags 	0 to get all information, or VSI_STAT_EXISTS_FLAG, VSI_STAT_NATURE_FLAG or VSI_STAT_SIZE_FLAG, or a combination of those to get partial info.
+//	flags 	0 to get all information, or VSI_STAT_EXISTS_FLAG, VSI_STAT_NATURE_FLAG or VSI_STAT_SIZE_FLAG, or a combination of those to get partial info. Information that was not requested may be left zero.
 //
 // Returns:
-//	statBuf 	the structure with information.
-//	success 	True on success or false on an error.
-func VSIStatExL(filename string, flags int) (statBuf VSIStatBufL, success bool) {
-	pszFilename := C.CString(filename)
-	defer C.free(unsafe.Pointer(pszFilename))
-
-	err := C.VSIStatExL(pszFilename, (*C.VSIStatBufL)(&statBuf), C.int(flags))
-	success = err == 0
-
-	return statBuf, success
+//	info 	the information about the object, named after the last element of filename.
+//	err 	a *fs.PathError on failure, matching fs.ErrNotExist with errors.Is if the object does not exist.
+func VSIStatExL(filename string, flags VSIStatFlags) (info fs.FileInfo, err error) {
+	fileInfo, err := vsiStatEx(filename, path.Base(filename), flags)
+	if err != nil {
+		return nil, err
+	}
+	return fileInfo, nil
 }
 
 // Returns if the filenames of the filesystem are case sensitive.
@@ -421,12 +421,19 @@ func VSICleanupFileManager() {
 //
 // A virtual memory file is created from the passed buffer with the indicated filename. Under normal conditions the filename would need to be absolute and within the /vsimem/ portion of the filesystem.
 //
-// The buffer remains the responsibility of the caller, and should not go out of scope as long as it might be accessed as a file. In no circumstances does this function take a copy of the data contents.
+// Go memory must not be kept by C, so the file is created from a copy of data, which the file owns. See also VSIMemWriteFile.
 func VSIFileFromMemBuffer(filename string, data []byte) VSILFile {
 	pszFilename := C.CString(filename)
 	defer C.free(unsafe.Pointer(pszFilename))
 
-	fp := C.VSIFileFromMemBuffer(pszFilename, (*C.GByte)(&data[0]), C.vsi_l_offset(len(data)), C.FALSE)
+	buffer := vsiCopyBytes(data)
+	if buffer == nil {
+		return nil
+	}
+	fp := C.VSIFileFromMemBuffer(pszFilename, buffer, C.vsi_l_offset(len(data)), C.TRUE)
+	if fp == nil {
+		C.VSIFree(unsafe.Pointer(buffer))
+	}
 	return VSILFile(fp)
 }
 
@@ -0,0 +1,330 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+*/
+import "C"
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Virtual files.                                                  */
+/* -------------------------------------------------------------------- */
+
+// File of the GDAL virtual file system, such as a /vsimem/ or /vsicurl/
+// file.  It implements io.ReadWriteSeeker, io.ReaderAt and io.Closer, so it
+// can be used with io.Copy, archive/zip or hashes.  Its methods may be
+// called from concurrent goroutines.
+type VSIFile struct {
+	name string
+	mu   sync.Mutex
+	fp   *C.VSILFILE
+}
+
+// Open a virtual file with the given access, as with fopen(): "r", "r+",
+// "w", "w+", "a" or "a+".  Binary access is always implied.
+func OpenVSIFile(filename, access string) (*VSIFile, error) {
+	cFilename := C.CString(filename)
+	defer C.free(unsafe.Pointer(cFilename))
+	cAccess := C.CString(access)
+	defer C.free(unsafe.Pointer(cAccess))
+
+	defer captureErrors()()
+	fp, errno := C.goVSIFOpen(cFilename, cAccess)
+	if fp == nil {
+		return nil, vsiError("open", filename, errno)
+	}
+	return &VSIFile{name: filename, fp: fp}, nil
+}
+
+// Fetch the name the file was opened with
+func (file *VSIFile) Name() string {
+	return file.name
+}
+
+// Read up to len(p) bytes at the current offset
+func (file *VSIFile) Read(p []byte) (int, error) {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+	return file.read(p)
+}
+
+// Read len(p) bytes at offset off, leaving the current offset unchanged
+func (file *VSIFile) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, &fs.PathError{Op: "readat", Path: file.name, Err: errors.New("negative offset")}
+	}
+
+	file.mu.Lock()
+	defer file.mu.Unlock()
+	if file.fp == nil {
+		return 0, &fs.PathError{Op: "readat", Path: file.name, Err: fs.ErrClosed}
+	}
+
+	defer captureErrors()()
+	saved := C.VSIFTellL(file.fp)
+	defer C.VSIFSeekL(file.fp, saved, C.SEEK_SET)
+	if ret, errno := C.VSIFSeekL(file.fp, C.vsi_l_offset(off), C.SEEK_SET); ret != 0 {
+		return 0, vsiError("seek", file.name, errno)
+	}
+
+	n := 0
+	for n < len(p) {
+		m, err := file.read(p[n:])
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Write len(p) bytes at the current offset
+func (file *VSIFile) Write(p []byte) (int, error) {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+	if file.fp == nil {
+		return 0, &fs.PathError{Op: "write", Path: file.name, Err: fs.ErrClosed}
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	defer captureErrors()()
+	n, errno := C.VSIFWriteL(unsafe.Pointer(&p[0]), 1, C.size_t(len(p)), file.fp)
+	if int(n) < len(p) {
+		return int(n), vsiError("write", file.name, errno)
+	}
+	return int(n), nil
+}
+
+// Set the offset of the next Read or Write, relative to the origin given by
+// whence: io.SeekStart, io.SeekCurrent or io.SeekEnd
+func (file *VSIFile) Seek(offset int64, whence int) (int64, error) {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+	if file.fp == nil {
+		return 0, &fs.PathError{Op: "seek", Path: file.name, Err: fs.ErrClosed}
+	}
+
+	defer captureErrors()()
+	// VSI offsets are unsigned, so relative offsets are resolved here
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += int64(C.VSIFTellL(file.fp))
+	case io.SeekEnd:
+		if ret, errno := C.VSIFSeekL(file.fp, 0, C.SEEK_END); ret != 0 {
+			return 0, vsiError("seek", file.name, errno)
+		}
+		offset += int64(C.VSIFTellL(file.fp))
+	default:
+		return 0, &fs.PathError{Op: "seek", Path: file.name, Err: errors.New("invalid whence")}
+	}
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: file.name, Err: errors.New("negative offset")}
+	}
+
+	if ret, errno := C.VSIFSeekL(file.fp, C.vsi_l_offset(offset), C.SEEK_SET); ret != 0 {
+		return 0, vsiError("seek", file.name, errno)
+	}
+	return offset, nil
+}
+
+// Flush pending writes
+func (file *VSIFile) Sync() error {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+	if file.fp == nil {
+		return &fs.PathError{Op: "sync", Path: file.name, Err: fs.ErrClosed}
+	}
+
+	defer captureErrors()()
+	if ret, errno := C.VSIFFlushL(file.fp); ret != 0 {
+		return vsiError("sync", file.name, errno)
+	}
+	return nil
+}
+
+// Change the size of the file
+func (file *VSIFile) Truncate(size int64) error {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+	if file.fp == nil {
+		return &fs.PathError{Op: "truncate", Path: file.name, Err: fs.ErrClosed}
+	}
+	if size < 0 {
+		return &fs.PathError{Op: "truncate", Path: file.name, Err: errors.New("negative size")}
+	}
+
+	defer captureErrors()()
+	if ret, errno := C.VSIFTruncateL(file.fp, C.vsi_l_offset(size)); ret != 0 {
+		return vsiError("truncate", file.name, errno)
+	}
+	return nil
+}
+
+// Fetch the size, type and modification time of the file
+func (file *VSIFile) Stat() (fs.FileInfo, error) {
+	return vsiStat(file.name, path.Base(file.name))
+}
+
+// Close the file.  Closing it again returns an error wrapping fs.ErrClosed.
+func (file *VSIFile) Close() error {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+	if file.fp == nil {
+		return &fs.PathError{Op: "close", Path: file.name, Err: fs.ErrClosed}
+	}
+
+	defer captureErrors()()
+	ret, errno := C.VSIFCloseL(file.fp)
+	file.fp = nil
+	if ret != 0 {
+		return vsiError("close", file.name, errno)
+	}
+	return nil
+}
+
+// Read at the current offset, with the lock held
+func (file *VSIFile) read(p []byte) (int, error) {
+	if file.fp == nil {
+		return 0, &fs.PathError{Op: "read", Path: file.name, Err: fs.ErrClosed}
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	defer captureErrors()()
+	n, errno := C.VSIFReadL(unsafe.Pointer(&p[0]), 1, C.size_t(len(p)), file.fp)
+	if int(n) < len(p) {
+		if C.VSIFEofL(file.fp) != 0 {
+			if n == 0 {
+				return 0, io.EOF
+			}
+			return int(n), nil
+		}
+		return int(n), vsiError("read", file.name, errno)
+	}
+	return int(n), nil
+}
+
+/* -------------------------------------------------------------------- */
+/*      File information.                                               */
+/* -------------------------------------------------------------------- */
+
+// File information returned by VSIStatL, VSIFile.Stat and VSIFS
+type vsiFileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (info *vsiFileInfo) Name() string       { return info.name }
+func (info *vsiFileInfo) Size() int64        { return info.size }
+func (info *vsiFileInfo) Mode() fs.FileMode  { return info.mode }
+func (info *vsiFileInfo) ModTime() time.Time { return info.modTime }
+func (info *vsiFileInfo) IsDir() bool        { return info.mode.IsDir() }
+func (info *vsiFileInfo) Sys() interface{}   { return nil }
+
+// POSIX file type bits, also used by VSI on Windows
+const (
+	vsiModeTypeMask = 0170000
+	vsiModeDir      = 0040000
+	vsiModeSymlink  = 0120000
+)
+
+// Convert a VSI st_mode to a file mode
+func vsiFileMode(mode uint32) fs.FileMode {
+	fileMode := fs.FileMode(mode & 0777)
+	switch mode & vsiModeTypeMask {
+	case vsiModeDir:
+		fileMode |= fs.ModeDir
+	case vsiModeSymlink:
+		fileMode |= fs.ModeSymlink
+	}
+	return fileMode
+}
+
+/* -------------------------------------------------------------------- */
+/*      Helper functions.                                               */
+/* -------------------------------------------------------------------- */
+
+// Fetch the information of a virtual file, under the given base name
+func vsiStat(filename, name string) (*vsiFileInfo, error) {
+	return vsiStatEx(filename, name, 0)
+}
+
+// Fetch the information requested by flags, see VSIStatExL
+func vsiStatEx(filename, name string, flags VSIStatFlags) (*vsiFileInfo, error) {
+	cFilename := C.CString(filename)
+	defer C.free(unsafe.Pointer(cFilename))
+
+	defer captureErrors()()
+	var statBuf C.VSIStatBufL
+	if ret, errno := C.VSIStatExL(cFilename, &statBuf, C.int(flags)); ret != 0 {
+		if errno == nil && !vsiErrorPending() {
+			// some filesystems report missing files without setting errno
+			// or raising any error
+			errno = syscall.ENOENT
+		}
+		return nil, vsiError("stat", filename, errno)
+	}
+	return &vsiFileInfo{
+		name:    name,
+		size:    int64(statBuf.st_size),
+		mode:    vsiFileMode(uint32(statBuf.st_mode)),
+		modTime: time.Unix(int64(C.goVSIStatMTime(&statBuf)), 0),
+	}, nil
+}
+
+// Return the error of a failed operation on a virtual file, taken from the
+// VSI error state, errno or the CPL error, in that order.  A VSI error
+// still matches errno with errors.Is, so that errors.Is(err,
+// fs.ErrNotExist) works whatever GDAL reported.
+func vsiError(op, filename string, errno error) error {
+	var cMsg *C.char
+	if num := C.goVSILastError(&cMsg); num != 0 {
+		err := &Error{CE_Failure, CPLE_FileIO, OGRERR_NONE, C.GoString(cMsg)}
+		if errno != nil {
+			return &fs.PathError{Op: op, Path: filename, Err: &vsiErrnoError{err, errno}}
+		}
+		return &fs.PathError{Op: op, Path: filename, Err: err}
+	}
+	if errno != nil {
+		return &fs.PathError{Op: op, Path: filename, Err: errno}
+	}
+	if err := pendingError(); err != nil {
+		return &fs.PathError{Op: op, Path: filename, Err: err}
+	}
+	return &fs.PathError{Op: op, Path: filename, Err: CPLE_FileIO}
+}
+
+// Report whether the failed operation raised a VSI or CPL error
+func vsiErrorPending() bool {
+	var cMsg *C.char
+	return C.goVSILastError(&cMsg) != 0 || pendingError() != nil
+}
+
+// VSI error along with the errno set by the failed operation
+type vsiErrnoError struct {
+	err   *Error
+	errno error
+}
+
+func (err *vsiErrnoError) Error() string {
+	return err.err.Error()
+}
+
+func (err *vsiErrnoError) Unwrap() []error {
+	return []error{err.err, err.errno}
+}
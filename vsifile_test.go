@@ -0,0 +1,104 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gdal
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"testing"
+)
+
+func TestVSIFile(t *testing.T) {
+	const name = "/vsimem/vsifile_test.bin"
+	content := []byte("0123456789abcdef")
+
+	file, err := OpenVSIFile(name, "w")
+	if err != nil {
+		t.Fatalf("OpenVSIFile: %v", err)
+	}
+	defer VSIUnlink(name)
+	if _, err := io.Copy(file, bytes.NewReader(content)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := file.Close(); !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("second Close: got %v, want fs.ErrClosed", err)
+	}
+
+	file, err = OpenVSIFile(name, "r")
+	if err != nil {
+		t.Fatalf("OpenVSIFile: %v", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil || !bytes.Equal(data, content) {
+		t.Errorf("ReadAll: got %q, %v, want %q", data, err, content)
+	}
+
+	if pos, err := file.Seek(-6, io.SeekEnd); err != nil || pos != 10 {
+		t.Errorf("Seek: got %d, %v, want 10", pos, err)
+	}
+	buf := make([]byte, 4)
+	if n, err := file.ReadAt(buf, 2); err != nil || string(buf[:n]) != "2345" {
+		t.Errorf("ReadAt: got %q, %v, want \"2345\"", buf[:n], err)
+	}
+	// ReadAt leaves the offset alone
+	if n, err := file.Read(buf); err != nil || string(buf[:n]) != "abcd" {
+		t.Errorf("Read after ReadAt: got %q, %v, want \"abcd\"", buf[:n], err)
+	}
+	if n, err := file.ReadAt(buf, 14); n != 2 || err != io.EOF {
+		t.Errorf("ReadAt past end: got %d, %v, want 2, io.EOF", n, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Size() != int64(len(content)) || info.IsDir() || info.Name() != "vsifile_test.bin" {
+		t.Errorf("Stat: got %s with %d bytes, dir %v", info.Name(), info.Size(), info.IsDir())
+	}
+
+	_, err = OpenVSIFile("/vsimem/no_such_file", "r")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("OpenVSIFile of missing file: got %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestVSIStat(t *testing.T) {
+	name, remove := VSIMemTempName("stat.bin")
+	defer remove()
+	if err := VSIMemWriteFile(name, []byte("content")); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := VSIStatL(name)
+	if err != nil {
+		t.Fatalf("VSIStatL: %v", err)
+	}
+	if info.Name() != "stat.bin" || info.Size() != 7 || info.IsDir() || !info.Mode().IsRegular() {
+		t.Errorf("VSIStatL: got name %q, size %d, mode %v", info.Name(), info.Size(), info.Mode())
+	}
+
+	if _, err := VSIStatExL(name, VSI_STAT_EXISTS_FLAG); err != nil {
+		t.Errorf("VSIStatExL of existing file: %v", err)
+	}
+	if info, err := VSIStatExL(name, VSI_STAT_NATURE_FLAG|VSI_STAT_SIZE_FLAG); err != nil || info.Size() != 7 {
+		t.Errorf("VSIStatExL: got %v, %v", info, err)
+	}
+	if info, err := VSIStatExL(path.Dir(name), VSI_STAT_NATURE_FLAG); err != nil || !info.IsDir() {
+		t.Errorf("VSIStatExL of directory: got %v, %v", info, err)
+	}
+	for _, flags := range []VSIStatFlags{0, VSI_STAT_EXISTS_FLAG, VSI_STAT_EXISTS_FLAG | VSI_STAT_SET_ERROR_FLAG} {
+		if _, err := VSIStatExL(name+".missing", flags); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("VSIStatExL of missing file with flags %#x: got %v, want fs.ErrNotExist", flags, err)
+		}
+	}
+}
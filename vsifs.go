@@ -0,0 +1,231 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+*/
+import "C"
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Directories.                                                    */
+/* -------------------------------------------------------------------- */
+
+// Fetch the names of the entries of a virtual directory, such as a
+// /vsimem/ directory or a directory of a /vsizip/ or /vsitar/ archive,
+// excluding "." and "..".  The names are in the order given by GDAL.
+func VSIReadDir(dirname string) ([]string, error) {
+	cDirname := C.CString(dirname)
+	defer C.free(unsafe.Pointer(cDirname))
+
+	defer captureErrors()()
+	list := C.VSIReadDir(cDirname)
+	defer C.CSLDestroy(list)
+	if list == nil {
+		if err := pendingError(); err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: dirname, Err: err}
+		}
+		return vsiEmptyDir("readdir", dirname)
+	}
+
+	names := []string{}
+	for _, name := range goStringList(list) {
+		if name != "." && name != ".." {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// Fetch the paths of the files and directories below a virtual directory,
+// relative to it.  The paths of directories end with a slash.
+func VSIReadDirRecursive(dirname string) ([]string, error) {
+	cDirname := C.CString(dirname)
+	defer C.free(unsafe.Pointer(cDirname))
+
+	defer captureErrors()()
+	list := C.VSIReadDirRecursive(cDirname)
+	defer C.CSLDestroy(list)
+	if list == nil {
+		return vsiEmptyDir("readdir", dirname)
+	}
+	return goStringList(list), nil
+}
+
+// GDAL lists empty and missing directories alike, tell them apart
+func vsiEmptyDir(op, dirname string) ([]string, error) {
+	info, err := vsiStat(dirname, path.Base(dirname))
+	if err != nil {
+		err.(*fs.PathError).Op = op
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, &fs.PathError{Op: op, Path: dirname, Err: errors.New("not a directory")}
+	}
+	return []string{}, nil
+}
+
+/* -------------------------------------------------------------------- */
+/*      Go filesystems over virtual directories.                        */
+/* -------------------------------------------------------------------- */
+
+// Return a read-only filesystem for the virtual files below prefix, such as
+// "/vsimem/tree" or "/vsizip/archive.zip", for use with fs.WalkDir, fs.Glob
+// or http.FS.  It implements fs.ReadDirFS and fs.StatFS.  Regular files are
+// opened as *VSIFile.  Listing a directory fetches the information of each
+// entry, which may be slow on network filesystems such as /vsicurl/.
+func VSIFS(prefix string) fs.FS {
+	return vsiFS(strings.TrimSuffix(prefix, "/"))
+}
+
+// Filesystem returned by VSIFS, with the prefix minus any trailing slash
+type vsiFS string
+
+// Convert a filesystem name to a GDAL filename
+func (fsys vsiFS) filename(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		if fsys == "" {
+			return "/", nil
+		}
+		return string(fsys), nil
+	}
+	return string(fsys) + "/" + name, nil
+}
+
+// Open a file or directory.  Directories implement fs.ReadDirFile.
+func (fsys vsiFS) Open(name string) (fs.File, error) {
+	info, err := fsys.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+	filename, _ := fsys.filename("open", name)
+	if info.IsDir() {
+		return &vsiDir{fsys: fsys, name: name, info: info}, nil
+	}
+
+	file, err := OpenVSIFile(filename, "r")
+	if err != nil {
+		return nil, renamePathError(err, name)
+	}
+	return file, nil
+}
+
+// Fetch the information of a file or directory
+func (fsys vsiFS) Stat(name string) (fs.FileInfo, error) {
+	info, err := fsys.stat("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Fetch the entries of a directory, sorted by name
+func (fsys vsiFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	filename, err := fsys.filename("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	names, err := VSIReadDir(filename)
+	if err != nil {
+		return nil, renamePathError(err, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]fs.DirEntry, 0, len(names))
+	for _, entry := range names {
+		info, err := fsys.stat("readdir", path.Join(name, entry))
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	return entries, nil
+}
+
+func (fsys vsiFS) stat(op, name string) (*vsiFileInfo, error) {
+	filename, err := fsys.filename(op, name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := vsiStat(filename, path.Base(name))
+	if err != nil {
+		err.(*fs.PathError).Op = op
+		return nil, renamePathError(err, name)
+	}
+	return info, nil
+}
+
+// Directory opened by vsiFS.Open
+type vsiDir struct {
+	fsys    vsiFS
+	name    string
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	read    bool
+	closed  bool
+}
+
+func (dir *vsiDir) Stat() (fs.FileInfo, error) {
+	return dir.info, nil
+}
+
+func (dir *vsiDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: dir.name, Err: errors.New("is a directory")}
+}
+
+func (dir *vsiDir) Close() error {
+	if dir.closed {
+		return &fs.PathError{Op: "close", Path: dir.name, Err: fs.ErrClosed}
+	}
+	dir.closed = true
+	return nil
+}
+
+// Fetch the next n entries of the directory, or all remaining ones if
+// n <= 0, as documented by fs.ReadDirFile
+func (dir *vsiDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if dir.closed {
+		return nil, &fs.PathError{Op: "readdir", Path: dir.name, Err: fs.ErrClosed}
+	}
+	if !dir.read {
+		entries, err := dir.fsys.ReadDir(dir.name)
+		if err != nil {
+			return nil, err
+		}
+		dir.entries, dir.read = entries, true
+	}
+
+	if n <= 0 {
+		entries := dir.entries
+		dir.entries = nil
+		return entries, nil
+	}
+	if len(dir.entries) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(dir.entries) {
+		n = len(dir.entries)
+	}
+	entries := dir.entries[:n:n]
+	dir.entries = dir.entries[n:]
+	return entries, nil
+}
+
+// Report a *fs.PathError with the filesystem name rather than the GDAL
+// filename, as fs.FS implementations do
+func renamePathError(err error, name string) error {
+	if pathErr, ok := err.(*fs.PathError); ok {
+		pathErr.Path = name
+	}
+	return err
+}
@@ -0,0 +1,123 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gdal
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/fs"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/fstest"
+)
+
+// Create a /vsimem/ tree holding a.txt, sub/b.txt and an empty directory
+func createVSIMemTree(t *testing.T) string {
+	root := "/vsimem/vsifs_test"
+	for _, dir := range []string{root, root + "/sub", root + "/empty"} {
+		if !VSIMkdir(dir, 0755) {
+			t.Fatalf("VSIMkdir %s failed", dir)
+		}
+	}
+	files := map[string]string{"a.txt": "alpha", "sub/b.txt": "beta"}
+	for name, content := range files {
+		if err := VSIMemWriteFile(root+"/"+name, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		for name := range files {
+			VSIMemRemove(root + "/" + name)
+		}
+		VSIRmdir(root + "/empty")
+		VSIRmdir(root + "/sub")
+		VSIRmdir(root)
+	})
+	return root
+}
+
+func TestVSIReadDir(t *testing.T) {
+	root := createVSIMemTree(t)
+
+	names, err := VSIReadDir(root)
+	sort.Strings(names)
+	if err != nil || !reflect.DeepEqual(names, []string{"a.txt", "empty", "sub"}) {
+		t.Errorf("VSIReadDir: got %q, %v", names, err)
+	}
+	if names, err := VSIReadDir(root + "/empty"); err != nil || len(names) != 0 {
+		t.Errorf("VSIReadDir of empty directory: got %q, %v", names, err)
+	}
+	if _, err := VSIReadDir(root + "/missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("VSIReadDir of missing directory: got %v, want fs.ErrNotExist", err)
+	}
+
+	names, err = VSIReadDirRecursive(root)
+	sort.Strings(names)
+	want := []string{"a.txt", "empty/", "sub/", "sub/b.txt"}
+	if err != nil || !reflect.DeepEqual(names, want) {
+		t.Errorf("VSIReadDirRecursive: got %q, %v, want %q", names, err, want)
+	}
+}
+
+func TestVSIFS(t *testing.T) {
+	root := createVSIMemTree(t)
+	fsys := VSIFS(root + "/")
+
+	if err := fstest.TestFS(fsys, "a.txt", "sub/b.txt", "empty"); err != nil {
+		t.Error(err)
+	}
+	if data, err := fs.ReadFile(fsys, "sub/b.txt"); err != nil || string(data) != "beta" {
+		t.Errorf("ReadFile: got %q, %v", data, err)
+	}
+	if matches, err := fs.Glob(fsys, "*/*.txt"); err != nil || !reflect.DeepEqual(matches, []string{"sub/b.txt"}) {
+		t.Errorf("Glob: got %q, %v", matches, err)
+	}
+	if _, err := fs.Stat(fsys, "missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat of missing file: got %v, want fs.ErrNotExist", err)
+	}
+	if _, err := fsys.Open("../vsifs_test"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Open of invalid path: got %v, want fs.ErrInvalid", err)
+	}
+}
+
+func TestVSIFSZip(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range []string{"a.txt", "dir/b.txt", "dir/c/d.txt"} {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(name))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	name, remove := VSIMemTempName("archive.zip")
+	defer remove()
+	if err := VSIMemWriteFile(name, buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	fsys := VSIFS("/vsizip/" + name)
+
+	var walked []string
+	err := fs.WalkDir(fsys, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		walked = append(walked, path)
+		return nil
+	})
+	want := []string{".", "a.txt", "dir", "dir/b.txt", "dir/c", "dir/c/d.txt"}
+	if err != nil || !reflect.DeepEqual(walked, want) {
+		t.Errorf("WalkDir: got %q, %v, want %q", walked, err, want)
+	}
+	if data, err := fs.ReadFile(fsys, "dir/c/d.txt"); err != nil || string(data) != "dir/c/d.txt" {
+		t.Errorf("ReadFile: got %q, %v", data, err)
+	}
+}
@@ -0,0 +1,165 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+)
+
+/* -------------------------------------------------------------------- */
+/*      In-memory files.                                                */
+/* -------------------------------------------------------------------- */
+
+// Create or replace a /vsimem/ file holding a copy of data
+func VSIMemWriteFile(filename string, data []byte) error {
+	if !strings.HasPrefix(filename, "/vsimem/") {
+		return newError(CPLE_IllegalArg, "Error: %s is not a /vsimem/ file", filename)
+	}
+	cFilename := C.CString(filename)
+	defer C.free(unsafe.Pointer(cFilename))
+
+	defer captureErrors()()
+	buffer := vsiCopyBytes(data)
+	if buffer == nil {
+		return lastError(CPLE_OutOfMemory, "Error: cannot allocate %d bytes for %s", len(data), filename)
+	}
+	fp, errno := C.VSIFileFromMemBuffer(cFilename, buffer, C.vsi_l_offset(len(data)), C.TRUE)
+	if fp == nil {
+		C.VSIFree(unsafe.Pointer(buffer))
+		return vsiError("write", filename, errno)
+	}
+	C.VSIFCloseL(fp)
+	return nil
+}
+
+// Fetch a copy of the content of a /vsimem/ file
+func VSIMemReadFile(filename string) ([]byte, error) {
+	if !strings.HasPrefix(filename, "/vsimem/") {
+		return nil, newError(CPLE_IllegalArg, "Error: %s is not a /vsimem/ file", filename)
+	}
+	cFilename := C.CString(filename)
+	defer C.free(unsafe.Pointer(cFilename))
+
+	defer captureErrors()()
+	var length C.vsi_l_offset
+	buffer := C.VSIGetMemFileBuffer(cFilename, &length, C.FALSE)
+	if buffer == nil {
+		// empty files may have no buffer
+		info, err := vsiStat(filename, path.Base(filename))
+		if err != nil {
+			err.(*fs.PathError).Op = "read"
+			return nil, err
+		}
+		if !info.Mode().IsRegular() || info.Size() != 0 {
+			return nil, &fs.PathError{Op: "read", Path: filename, Err: errors.New("not a regular /vsimem/ file")}
+		}
+		return []byte{}, nil
+	}
+
+	data := make([]byte, int(length))
+	copy(data, unsafe.Slice((*byte)(unsafe.Pointer(buffer)), int(length)))
+	return data, nil
+}
+
+// Remove a /vsimem/ file
+func VSIMemRemove(filename string) error {
+	cFilename := C.CString(filename)
+	defer C.free(unsafe.Pointer(cFilename))
+
+	defer captureErrors()()
+	if ret, errno := C.VSIUnlink(cFilename); ret != 0 {
+		return vsiError("remove", filename, errno)
+	}
+	return nil
+}
+
+var vsiMemTempCount atomic.Uint64
+
+// Generate a unique /vsimem/ filename ending with base, in a directory of
+// its own.  The returned function removes the file, along with any sidecar
+// file GDAL created next to it, such as .aux.xml or .ovr files.
+func VSIMemTempName(base string) (string, func()) {
+	dir := fmt.Sprintf("/vsimem/go_gdal_%d", vsiMemTempCount.Add(1))
+	return dir + "/" + base, func() { removeVSIMemDir(dir) }
+}
+
+// Open a raster dataset from a copy of data, such as the content of a
+// GeoTIFF or PNG file.  The copy is released along with the dataset.
+func OpenBytes(data []byte) (Dataset, error) {
+	filename, remove := VSIMemTempName("data")
+	if err := VSIMemWriteFile(filename, data); err != nil {
+		remove()
+		return Dataset{}, err
+	}
+
+	dataset, err := Open(filename, ReadOnly)
+	if err != nil {
+		remove()
+		return Dataset{}, err
+	}
+	dataset.owner.disown()
+	cval := dataset.cval
+	return Dataset{cval, own(func() { C.GDALClose(cval); remove() })}, nil
+}
+
+// Encode the dataset in the format of driver, such as a GeoTIFF or PNG,
+// without touching disk.  Formats writing several files return the main
+// one only.
+func (dataset Dataset) Bytes(driver Driver, options []string) ([]byte, error) {
+	base := "data"
+	if ext := driver.MetadataItem(DMD_EXTENSION, ""); ext != "" {
+		base += "." + ext
+	}
+	filename, remove := VSIMemTempName(base)
+	defer remove()
+
+	encoded, err := driver.createCopy(filename, dataset, 0, options, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	encoded.Close()
+	return VSIMemReadFile(filename)
+}
+
+/* -------------------------------------------------------------------- */
+/*      Helper functions.                                               */
+/* -------------------------------------------------------------------- */
+
+// Copy data to memory allocated by VSIMalloc, returning nil if out of
+// memory.  At least one byte is allocated, so that empty data yields a
+// valid buffer.
+func vsiCopyBytes(data []byte) *C.GByte {
+	size := len(data)
+	if size == 0 {
+		size = 1
+	}
+	buffer := C.VSIMalloc(C.size_t(size))
+	if buffer == nil {
+		return nil
+	}
+	copy(unsafe.Slice((*byte)(buffer), size), data)
+	return (*C.GByte)(buffer)
+}
+
+// Remove a /vsimem/ directory and the files in it
+func removeVSIMemDir(dir string) {
+	cDir := C.CString(dir)
+	defer C.free(unsafe.Pointer(cDir))
+
+	list := C.VSIReadDir(cDir)
+	defer C.CSLDestroy(list)
+	for _, name := range goStringList(list) {
+		cName := C.CString(dir + "/" + name)
+		C.VSIUnlink(cName)
+		C.free(unsafe.Pointer(cName))
+	}
+	C.VSIRmdir(cDir)
+}
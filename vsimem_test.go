@@ -0,0 +1,93 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gdal
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"testing"
+)
+
+func TestVSIMem(t *testing.T) {
+	name, remove := VSIMemTempName("file.bin")
+	content := []byte("content")
+	if err := VSIMemWriteFile(name, content); err != nil {
+		t.Fatalf("VSIMemWriteFile: %v", err)
+	}
+	// the file holds a copy
+	content[0] = 'C'
+	if data, err := VSIMemReadFile(name); err != nil || string(data) != "content" {
+		t.Errorf("VSIMemReadFile: got %q, %v", data, err)
+	}
+
+	if err := VSIMemWriteFile(name+".aux.xml", nil); err != nil {
+		t.Fatalf("VSIMemWriteFile of empty file: %v", err)
+	}
+	if data, err := VSIMemReadFile(name + ".aux.xml"); err != nil || len(data) != 0 {
+		t.Errorf("VSIMemReadFile of empty file: got %q, %v", data, err)
+	}
+
+	remove()
+	for _, removed := range []string{name, name + ".aux.xml"} {
+		if _, err := VSIMemReadFile(removed); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("VSIMemReadFile of removed %s: got %v, want fs.ErrNotExist", removed, err)
+		}
+	}
+	if err := VSIMemRemove(name); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("VSIMemRemove of removed file: got %v, want fs.ErrNotExist", err)
+	}
+	if err := VSIMemWriteFile("/tmp/file.bin", content); !errors.Is(err, CPLE_IllegalArg) {
+		t.Errorf("VSIMemWriteFile outside /vsimem/: got %v, want illegal argument", err)
+	}
+	if _, err := VSIMemReadFile("/etc/hostname"); !errors.Is(err, CPLE_IllegalArg) {
+		t.Errorf("VSIMemReadFile outside /vsimem/: got %v, want illegal argument", err)
+	}
+	dir := "/vsimem/vsimem_test_dir"
+	if !VSIMkdir(dir, 0755) {
+		t.Fatalf("VSIMkdir %s failed", dir)
+	}
+	defer VSIRmdir(dir)
+	if data, err := VSIMemReadFile(dir); err == nil {
+		t.Errorf("VSIMemReadFile of directory: got %q, want error", data)
+	}
+}
+
+func TestOpenBytes(t *testing.T) {
+	src := createMemoryRaster(t, 8, 4, Byte)
+	defer src.Close()
+	if err := WriteWindow(src.RasterBand(1), 0, 0, 8, 4, bytes.Repeat([]uint8{7}, 32)); err != nil {
+		t.Fatal(err)
+	}
+
+	driver, err := GetDriverByName("GTiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tiff, err := src.Bytes(driver, []string{"COMPRESS=DEFLATE"})
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	if !bytes.HasPrefix(tiff, []byte("II*\x00")) && !bytes.HasPrefix(tiff, []byte("MM\x00*")) {
+		t.Fatalf("Bytes: not a TIFF: %q", tiff[:8])
+	}
+
+	dataset, err := OpenBytes(tiff)
+	if err != nil {
+		t.Fatalf("OpenBytes: %v", err)
+	}
+	defer dataset.Close()
+	data, err := ReadWindow[uint8](dataset.RasterBand(1), 0, 0, 8, 4)
+	if err != nil {
+		t.Fatalf("ReadWindow: %v", err)
+	}
+	if !bytes.Equal(data, bytes.Repeat([]uint8{7}, 32)) {
+		t.Errorf("ReadWindow: got %v", data)
+	}
+
+	if _, err := OpenBytes([]byte("not a raster")); err == nil {
+		t.Error("OpenBytes of garbage: no error")
+	}
+}
@@ -0,0 +1,234 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+*/
+import "C"
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"runtime/cgo"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+/* -------------------------------------------------------------------- */
+/*      Go filesystem plugins.                                          */
+/* -------------------------------------------------------------------- */
+
+// Read-only filesystem implemented in Go and served to GDAL by
+// InstallVSIPlugin.  Names are slash separated paths relative to the
+// prefix the plugin is installed under, with "." for the prefix itself.
+// Errors matching fs.ErrNotExist tell GDAL that a file does not exist.
+// The methods are called from the threads of GDAL, possibly concurrently,
+// and must not panic.
+type VSIPlugin interface {
+	// Open a file for reading
+	Open(name string) (VSIPluginFile, error)
+	// Fetch the size, type and modification time of a file or directory
+	Stat(name string) (fs.FileInfo, error)
+	// List the names of the entries of a directory
+	ReadDir(name string) ([]string, error)
+}
+
+// File opened by a VSIPlugin.  GDAL reads it through ReadAt only, into
+// buffers that must not be retained after ReadAt returned.
+type VSIPluginFile interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// Install a Go filesystem under a prefix such as "/vsigo/mystore/", so that
+// every GDAL and OGR driver can read the files named "/vsigo/mystore/...".
+// The plugin stays installed for the lifetime of the process.  It requires
+// GDAL 3.0 or newer.
+func InstallVSIPlugin(prefix string, plugin VSIPlugin) error {
+	if !strings.HasPrefix(prefix, "/") || !strings.HasSuffix(prefix, "/") {
+		return newError(CPLE_IllegalArg, "Error: plugin prefix %q must start and end with /", prefix)
+	}
+	cPrefix := C.CString(prefix)
+	defer C.free(unsafe.Pointer(cPrefix))
+
+	handle := cgo.NewHandle(&vsiPlugin{prefix, plugin})
+
+	defer captureErrors()()
+	if C.goVSIInstallPlugin(cPrefix, C.uintptr_t(handle)) != 0 {
+		handle.Delete()
+		return lastError(CPLE_AppDefined, "Error: failed to install plugin under %s", prefix)
+	}
+	return nil
+}
+
+// Plugin installed under a prefix
+type vsiPlugin struct {
+	prefix string
+	plugin VSIPlugin
+}
+
+// Convert a GDAL filename to a plugin name
+func (p *vsiPlugin) name(filename string) string {
+	name := strings.TrimPrefix(filename, p.prefix)
+	if name == "" || name == strings.TrimSuffix(p.prefix, "/") {
+		return "."
+	}
+	return path.Clean(name)
+}
+
+// File opened through a plugin, with the read offset kept by GDAL
+type vsiPluginFile struct {
+	mu     sync.Mutex
+	file   VSIPluginFile
+	size   int64
+	offset int64
+	eof    bool
+}
+
+// Status of plugin calls, see go_gdal.c
+const (
+	vsiPluginOK       = 0
+	vsiPluginNotExist = 1
+	vsiPluginFailed   = 2
+)
+
+// Report the error of a plugin call to GDAL, unless the file does not exist
+func vsiPluginStatus(err error) C.int {
+	if err == nil {
+		return vsiPluginOK
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return vsiPluginNotExist
+	}
+	cMsg := C.CString(err.Error())
+	defer C.free(unsafe.Pointer(cMsg))
+	C.goGDALReportError(C.int(CPLE_FileIO), cMsg)
+	return vsiPluginFailed
+}
+
+//export goVSIPluginStat
+func goVSIPluginStat(handle C.uintptr_t, filename *C.char, size *C.longlong, isDir *C.int, mtime *C.longlong) C.int {
+	p := cgo.Handle(handle).Value().(*vsiPlugin)
+	info, err := p.plugin.Stat(p.name(C.GoString(filename)))
+	if err != nil {
+		// GDAL probes for many optional files, missing ones are no error
+		return vsiPluginStatus(err)
+	}
+	*size = C.longlong(info.Size())
+	*isDir = 0
+	if info.IsDir() {
+		*isDir = 1
+	}
+	*mtime = C.longlong(info.ModTime().Unix())
+	return vsiPluginOK
+}
+
+//export goVSIPluginReadDir
+func goVSIPluginReadDir(handle C.uintptr_t, dirname *C.char, maxFiles C.int) **C.char {
+	p := cgo.Handle(handle).Value().(*vsiPlugin)
+	names, err := p.plugin.ReadDir(p.name(C.GoString(dirname)))
+	if err != nil {
+		vsiPluginStatus(err)
+		return nil
+	}
+	if maxFiles > 0 && len(names) > int(maxFiles) {
+		names = names[:maxFiles]
+	}
+	return cStringList(names)
+}
+
+//export goVSIPluginOpen
+func goVSIPluginOpen(handle C.uintptr_t, filename, access *C.char, status *C.int) C.uintptr_t {
+	p := cgo.Handle(handle).Value().(*vsiPlugin)
+	name := p.name(C.GoString(filename))
+	if mode := C.GoString(access); strings.ContainsAny(mode, "wa+") {
+		*status = vsiPluginStatus(&fs.PathError{Op: "open", Path: name, Err: errors.New("read-only filesystem")})
+		return 0
+	}
+
+	info, err := p.plugin.Stat(name)
+	if err != nil {
+		*status = vsiPluginStatus(err)
+		return 0
+	}
+	file, err := p.plugin.Open(name)
+	if err != nil {
+		*status = vsiPluginStatus(err)
+		return 0
+	}
+	*status = vsiPluginOK
+	return C.uintptr_t(cgo.NewHandle(&vsiPluginFile{file: file, size: info.Size()}))
+}
+
+//export goVSIPluginTell
+func goVSIPluginTell(handle C.uintptr_t) C.vsi_l_offset {
+	f := cgo.Handle(handle).Value().(*vsiPluginFile)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return C.vsi_l_offset(f.offset)
+}
+
+//export goVSIPluginSeek
+func goVSIPluginSeek(handle C.uintptr_t, offset C.vsi_l_offset, whence C.int) C.int {
+	f := cgo.Handle(handle).Value().(*vsiPluginFile)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch whence {
+	case C.SEEK_SET:
+		f.offset = int64(offset)
+	case C.SEEK_CUR:
+		f.offset += int64(offset)
+	case C.SEEK_END:
+		f.offset = f.size + int64(offset)
+	default:
+		return -1
+	}
+	f.eof = false
+	return 0
+}
+
+//export goVSIPluginRead
+func goVSIPluginRead(handle C.uintptr_t, buffer unsafe.Pointer, length C.size_t) C.size_t {
+	f := cgo.Handle(handle).Value().(*vsiPluginFile)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.offset >= f.size {
+		f.eof = true
+		return 0
+	}
+
+	p := unsafe.Slice((*byte)(buffer), int(length))
+	if remaining := f.size - f.offset; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := f.file.ReadAt(p, f.offset)
+	f.offset += int64(n)
+	if err == io.EOF || f.offset >= f.size {
+		f.eof = n < int(length)
+	} else if err != nil {
+		vsiPluginStatus(err)
+	}
+	return C.size_t(n)
+}
+
+//export goVSIPluginEof
+func goVSIPluginEof(handle C.uintptr_t) C.int {
+	f := cgo.Handle(handle).Value().(*vsiPluginFile)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.eof {
+		return 1
+	}
+	return 0
+}
+
+//export goVSIPluginClose
+func goVSIPluginClose(handle C.uintptr_t) C.int {
+	f := cgo.Handle(handle).Value().(*vsiPluginFile)
+	cgo.Handle(handle).Delete()
+	if vsiPluginStatus(f.file.Close()) != vsiPluginOK {
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,172 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gdal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Read-only plugin serving files from a map
+type mapPlugin map[string][]byte
+
+type mapFileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (info mapFileInfo) Name() string       { return info.name }
+func (info mapFileInfo) Size() int64        { return info.size }
+func (info mapFileInfo) ModTime() time.Time { return time.Time{} }
+func (info mapFileInfo) IsDir() bool        { return info.dir }
+func (info mapFileInfo) Sys() interface{}   { return nil }
+func (info mapFileInfo) Mode() fs.FileMode {
+	if info.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+type mapFile struct {
+	*bytes.Reader
+}
+
+func (mapFile) Close() error { return nil }
+
+func (p mapPlugin) Open(name string) (VSIPluginFile, error) {
+	data, ok := p[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return mapFile{bytes.NewReader(data)}, nil
+}
+
+func (p mapPlugin) Stat(name string) (fs.FileInfo, error) {
+	if name == "." {
+		return mapFileInfo{name, 0, true}, nil
+	}
+	data, ok := p[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return mapFileInfo{name, int64(len(data)), false}, nil
+}
+
+func (p mapPlugin) ReadDir(name string) ([]string, error) {
+	var names []string
+	for file := range p {
+		names = append(names, file)
+	}
+	return names, nil
+}
+
+func TestVSIPlugin(t *testing.T) {
+	if VERSION_NUM < 3000000 {
+		t.Skip("filesystem plugins require GDAL 3.0")
+	}
+
+	// a GeoTIFF to serve
+	src := createMemoryRaster(t, 8, 4, Byte)
+	defer src.Close()
+	driver, err := GetDriverByName("GTiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tiff, err := driver.CreateCopyContext(context.Background(), "/vsimem/plugin_test.tif", src, 0, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tiff.Close()
+	tiffBytes := VSIGetMemFileBuffer("/vsimem/plugin_test.tif", true)
+
+	plugin := mapPlugin{"hello.txt": []byte("hello, world"), "raster.tif": tiffBytes}
+	if err := InstallVSIPlugin("/vsigo/test/", plugin); err != nil {
+		t.Fatalf("InstallVSIPlugin: %v", err)
+	}
+
+	file, err := OpenVSIFile("/vsigo/test/hello.txt", "r")
+	if err != nil {
+		t.Fatalf("OpenVSIFile: %v", err)
+	}
+	data, err := io.ReadAll(file)
+	if err != nil || string(data) != "hello, world" {
+		t.Errorf("ReadAll: got %q, %v", data, err)
+	}
+	file.Close()
+
+	if _, err := OpenVSIFile("/vsigo/test/missing.txt", "r"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("OpenVSIFile of missing file: got %v, want fs.ErrNotExist", err)
+	}
+	if _, err := OpenVSIFile("/vsigo/test/hello.txt", "w"); err == nil {
+		t.Error("OpenVSIFile for writing: no error")
+	}
+
+	dataset, err := Open("/vsigo/test/raster.tif", ReadOnly)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer dataset.Close()
+	if dataset.RasterXSize() != 8 || dataset.RasterYSize() != 4 {
+		t.Errorf("Open: got %dx%d raster, want 8x4", dataset.RasterXSize(), dataset.RasterYSize())
+	}
+
+	if err := InstallVSIPlugin("no/slashes", plugin); !errors.Is(err, CPLE_IllegalArg) {
+		t.Errorf("InstallVSIPlugin with bad prefix: got %v, want illegal argument", err)
+	}
+}
+
+// Plugin whose "broken" entries fail with an error other than not-exist
+type failingPlugin struct {
+	mapPlugin
+}
+
+var errBackend = errors.New("backend unavailable")
+
+func (p failingPlugin) Stat(name string) (fs.FileInfo, error) {
+	switch name {
+	case "broken.txt":
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: errBackend}
+	case "broken":
+		return mapFileInfo{name, 0, true}, nil
+	}
+	return p.mapPlugin.Stat(name)
+}
+
+func (p failingPlugin) ReadDir(name string) ([]string, error) {
+	if name == "broken" {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errBackend}
+	}
+	return p.mapPlugin.ReadDir(name)
+}
+
+func TestVSIPluginErrors(t *testing.T) {
+	if VERSION_NUM < 3000000 {
+		t.Skip("filesystem plugins require GDAL 3.0")
+	}
+	plugin := failingPlugin{mapPlugin{"hello.txt": []byte("hello")}}
+	if err := InstallVSIPlugin("/vsigo/failing/", plugin); err != nil {
+		t.Fatalf("InstallVSIPlugin: %v", err)
+	}
+
+	_, err := VSIStatL("/vsigo/failing/broken.txt")
+	if err == nil || errors.Is(err, fs.ErrNotExist) || !strings.Contains(err.Error(), errBackend.Error()) {
+		t.Errorf("VSIStatL of failing file: got %v, want %q", err, errBackend)
+	}
+	if _, err := VSIStatL("/vsigo/failing/missing.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("VSIStatL of missing file: got %v, want fs.ErrNotExist", err)
+	}
+
+	_, err = VSIReadDir("/vsigo/failing/broken")
+	if err == nil || !strings.Contains(err.Error(), errBackend.Error()) {
+		t.Errorf("VSIReadDir of failing directory: got %v, want %q", err, errBackend)
+	}
+}